@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/pavlovic265/265-gt/constants"
 	helpers "github.com/pavlovic265/265-gt/helpers"
@@ -15,8 +17,14 @@ type CliClient interface {
 	CreatePullRequest(ctx context.Context, args []string) error
 	ListPullRequests(ctx context.Context, args []string) ([]PullRequest, error)
 	HasOpenPullRequestForBranch(ctx context.Context, branch string) (bool, error)
+	GetPullRequestState(ctx context.Context, branch string) (PullRequestState, error)
 	MergePullRequest(ctx context.Context, prNumber int) error
 	UpdatePullRequestBaseBranch(ctx context.Context, branch string) error
+	RenameBranch(ctx context.Context, branch string, newName string) error
+	ClosePullRequest(ctx context.Context, branch string) error
+	// GetPullRequest looks up a pull request by number, or the open pull
+	// request whose head is the given branch; nil when the branch has none.
+	GetPullRequest(ctx context.Context, ref string) (*PullRequest, error)
 }
 
 func NewRestCliClient(platform constants.Platform, gitHelper helpers.GitHelper) (CliClient, error) {
@@ -29,3 +37,21 @@ func NewRestCliClient(platform constants.Platform, gitHelper helpers.GitHelper)
 		return nil, fmt.Errorf("unsupported platform: %q", platform)
 	}
 }
+
+// baseBranch is the branch a pull request for branch targets: its stack parent,
+// or the trunk for branches that are not tracked.
+func baseBranch(ctx context.Context, gitHelper helpers.GitHelper, branch string) (string, error) {
+	if parent, err := gitHelper.GetParent(branch); err == nil && parent != "" {
+		return parent, nil
+	}
+	return gitHelper.GetTrunk(ctx)
+}
+
+// parsePullRequestNumber accepts "123" and "#123"; anything else is a branch.
+func parsePullRequestNumber(ref string) (int, bool) {
+	number, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return number, true
+}
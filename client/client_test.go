@@ -51,3 +51,12 @@ func TestNewRestCliClient(t *testing.T) {
 		t.Fatal("expected unsupported platform error")
 	}
 }
+
+func TestParsePullRequestNumber(t *testing.T) {
+	for ref, want := range map[string]int{"42": 42, "#7": 7, "feature/42": 0, "0": 0, "": 0} {
+		got, ok := parsePullRequestNumber(ref)
+		if got != want || ok != (want > 0) {
+			t.Errorf("parsePullRequestNumber(%q) = %d, %v; want %d", ref, got, ok, want)
+		}
+	}
+}
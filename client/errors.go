@@ -6,4 +6,5 @@ import "errors"
 var (
 	ErrConfigNotLoaded = errors.New("config not loaded")
 	ErrNoActiveAccount = errors.New("no active account")
+	ErrNotSupported    = errors.New("not supported by this platform")
 )
@@ -209,7 +209,7 @@ func (c *gitHubClient) CreatePullRequest(ctx context.Context, args []string) err
 		return err
 	}
 
-	parent, err := c.gitHelper.GetParent(branch)
+	parent, err := baseBranch(ctx, c.gitHelper, branch)
 	if err != nil {
 		return err
 	}
@@ -343,6 +343,7 @@ func (c *gitHubClient) ListPullRequests(ctx context.Context, args []string) ([]P
 							Login string `json:"login"`
 						} `json:"author"`
 						HeadRefName string `json:"headRefName"`
+						BaseRefName string `json:"baseRefName"`
 						Commits     struct {
 							Nodes []struct {
 								Commit struct {
@@ -381,6 +382,7 @@ func (c *gitHubClient) ListPullRequests(ctx context.Context, args []string) ([]P
 			URL:         pr.URL,
 			Author:      pr.Author.Login,
 			Branch:      pr.HeadRefName,
+			BaseBranch:  pr.BaseRefName,
 			Mergeable:   pr.Mergeable,
 			StatusState: mapGraphQLStatusState(pr.Commits),
 			ReviewState: mapGraphQLReviewDecision(pr.ReviewDecision),
@@ -467,6 +469,55 @@ func (c *gitHubClient) HasOpenPullRequestForBranch(
 	return len(ghPRs) > 0, nil
 }
 
+func (c *gitHubClient) GetPullRequestState(
+	ctx context.Context, branch string,
+) (PullRequestState, error) {
+	repoInfo, account, err := c.getRepoInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("state", "all")
+	query.Set("head", fmt.Sprintf("%s:%s", repoInfo.Owner, branch))
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/pulls?%s",
+		githubAPIBase, repoInfo.Owner, repoInfo.Repo, query.Encode())
+
+	resp, err := c.doRequest(ctx, "GET", apiURL, nil, account.Token)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to list PRs: %s", resp.Status)
+	}
+
+	var ghPRs []struct {
+		State    string  `json:"state"`
+		MergedAt *string `json:"merged_at"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&ghPRs); err != nil {
+		return "", err
+	}
+
+	if len(ghPRs) == 0 {
+		return "", nil
+	}
+
+	// Results are ordered newest first, so the first entry is the latest PR for the branch.
+	switch {
+	case ghPRs[0].MergedAt != nil:
+		return PullRequestStateMerged, nil
+	case ghPRs[0].State == "closed":
+		return PullRequestStateClosed, nil
+	default:
+		return PullRequestStateOpen, nil
+	}
+}
+
 func (c *gitHubClient) getPullRequestNumberForBranch(
 	ctx context.Context, branch string,
 ) (int, error) {
@@ -639,6 +690,136 @@ func (c *gitHubClient) UpdatePullRequestBaseBranch(ctx context.Context, branch s
 	return nil
 }
 
+func (c *gitHubClient) RenameBranch(ctx context.Context, branch string, newName string) error {
+	repoInfo, account, err := c.getRepoInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/branches/%s/rename",
+		githubAPIBase, repoInfo.Owner, repoInfo.Repo, url.PathEscape(branch))
+	resp, err := c.doRequest(ctx, "POST", apiURL, map[string]string{
+		"new_name": newName,
+	}, account.Token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("failed to rename branch: %s", errResp.Message)
+	}
+
+	return nil
+}
+
+func (c *gitHubClient) ClosePullRequest(ctx context.Context, branch string) error {
+	repoInfo, account, err := c.getRepoInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	prNumber, err := c.getPullRequestNumberForBranch(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if prNumber == 0 {
+		return nil
+	}
+
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", githubAPIBase, repoInfo.Owner, repoInfo.Repo, prNumber)
+	resp, err := c.doRequest(ctx, "PATCH", apiURL, map[string]string{
+		"state": "closed",
+	}, account.Token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("failed to close PR: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *gitHubClient) GetPullRequest(ctx context.Context, ref string) (*PullRequest, error) {
+	repoInfo, account, err := c.getRepoInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	number, byNumber := parsePullRequestNumber(ref)
+
+	var apiURL string
+	if byNumber {
+		apiURL = fmt.Sprintf("%s/repos/%s/%s/pulls/%d", githubAPIBase, repoInfo.Owner, repoInfo.Repo, number)
+	} else {
+		query := url.Values{}
+		query.Set("state", "open")
+		query.Set("head", fmt.Sprintf("%s:%s", repoInfo.Owner, ref))
+		apiURL = fmt.Sprintf("%s/repos/%s/%s/pulls?%s",
+			githubAPIBase, repoInfo.Owner, repoInfo.Repo, query.Encode())
+	}
+
+	resp, err := c.doRequest(ctx, "GET", apiURL, nil, account.Token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && byNumber {
+		return nil, fmt.Errorf("pull request #%d not found", number)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get PR: %s", resp.Status)
+	}
+
+	type ghPullRequest struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	}
+
+	var pr ghPullRequest
+	if byNumber {
+		if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+			return nil, err
+		}
+	} else {
+		var ghPRs []ghPullRequest
+		if err := json.NewDecoder(resp.Body).Decode(&ghPRs); err != nil {
+			return nil, err
+		}
+		if len(ghPRs) == 0 {
+			return nil, nil
+		}
+		pr = ghPRs[0]
+	}
+
+	return &PullRequest{
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		Author:     pr.User.Login,
+		Branch:     pr.Head.Ref,
+		BaseBranch: pr.Base.Ref,
+	}, nil
+}
+
 type PullRequest struct {
 	Number      int             `json:"number"`
 	Title       string          `json:"title"`
@@ -646,11 +827,20 @@ type PullRequest struct {
 	Author      string          `json:"author"`
 	Mergeable   string          `json:"mergeable"`
 	Branch      string          `json:"headRefName"`
+	BaseBranch  string          `json:"baseRefName"`
 	StatusState StatusStateType `json:"statusState"`
 	ReviewState ReviewStateType `json:"reviewState"`
 	MergeQueued bool            `json:"mergeQueued"`
 }
 
+type PullRequestState string
+
+const (
+	PullRequestStateOpen   PullRequestState = "OPEN"
+	PullRequestStateMerged PullRequestState = "MERGED"
+	PullRequestStateClosed PullRequestState = "CLOSED"
+)
+
 type StatusStateType string
 
 const (
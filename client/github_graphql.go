@@ -15,6 +15,7 @@ query ListOpenPullRequests($owner: String!, $repo: String!) {
           login
         }
         headRefName
+        baseRefName
         commits(last: 1) {
           nodes {
             commit {
@@ -156,7 +156,7 @@ func (c *gitLabClient) CreatePullRequest(ctx context.Context, args []string) err
 		return err
 	}
 
-	parent, err := c.gitHelper.GetParent(branch)
+	parent, err := baseBranch(ctx, c.gitHelper, branch)
 	if err != nil {
 		return err
 	}
@@ -221,6 +221,7 @@ func (c *gitLabClient) ListPullRequests(ctx context.Context, args []string) ([]P
 			Username string `json:"username"`
 		} `json:"author"`
 		SourceBranch              string `json:"source_branch"`
+		TargetBranch              string `json:"target_branch"`
 		MergeStatus               string `json:"merge_status"`
 		MergeWhenPipelineSucceeds bool   `json:"merge_when_pipeline_succeeds"`
 	}
@@ -247,6 +248,7 @@ func (c *gitLabClient) ListPullRequests(ctx context.Context, args []string) ([]P
 			URL:         mr.WebURL,
 			Author:      mr.Author.Username,
 			Branch:      mr.SourceBranch,
+			BaseBranch:  mr.TargetBranch,
 			Mergeable:   mergeable,
 			ReviewState: reviewState,
 			MergeQueued: mr.MergeWhenPipelineSucceeds,
@@ -290,6 +292,52 @@ func (c *gitLabClient) HasOpenPullRequestForBranch(
 	return len(glMRs) > 0, nil
 }
 
+func (c *gitLabClient) GetPullRequestState(
+	ctx context.Context, branch string,
+) (PullRequestState, error) {
+	projectPath, account, err := c.getProjectInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("source_branch", branch)
+
+	apiURL := fmt.Sprintf("%s/projects/%s/merge_requests?%s",
+		gitlabAPIBase, projectPath, query.Encode())
+
+	resp, err := c.doRequest(ctx, "GET", apiURL, nil, account.Token)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to list MRs: %s", resp.Status)
+	}
+
+	var glMRs []struct {
+		State string `json:"state"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&glMRs); err != nil {
+		return "", err
+	}
+
+	if len(glMRs) == 0 {
+		return "", nil
+	}
+
+	switch glMRs[0].State {
+	case "merged":
+		return PullRequestStateMerged, nil
+	case "closed":
+		return PullRequestStateClosed, nil
+	default:
+		return PullRequestStateOpen, nil
+	}
+}
+
 func (c *gitLabClient) getMergeRequestIIDForBranch(
 	ctx context.Context, branch string,
 ) (int, error) {
@@ -441,3 +489,110 @@ func (c *gitLabClient) UpdatePullRequestBaseBranch(ctx context.Context, branch s
 
 	return nil
 }
+
+func (c *gitLabClient) ClosePullRequest(ctx context.Context, branch string) error {
+	projectPath, account, err := c.getProjectInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	prNumber, err := c.getMergeRequestIIDForBranch(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if prNumber == 0 {
+		return nil
+	}
+
+	query := url.Values{}
+	query.Set("state_event", "close")
+
+	apiURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d?%s",
+		gitlabAPIBase, projectPath, prNumber, query.Encode())
+	resp, err := c.doRequest(ctx, "PUT", apiURL, nil, account.Token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("failed to close MR: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// RenameBranch is not available on GitLab: the API has no branch rename and a
+// merge request's source branch cannot be changed.
+func (c *gitLabClient) RenameBranch(ctx context.Context, branch string, newName string) error {
+	return fmt.Errorf("renaming branches: %w", ErrNotSupported)
+}
+
+func (c *gitLabClient) GetPullRequest(ctx context.Context, ref string) (*PullRequest, error) {
+	projectPath, account, err := c.getProjectInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	number, byNumber := parsePullRequestNumber(ref)
+
+	var apiURL string
+	if byNumber {
+		apiURL = fmt.Sprintf("%s/projects/%s/merge_requests/%d", gitlabAPIBase, projectPath, number)
+	} else {
+		query := url.Values{}
+		query.Set("state", "opened")
+		query.Set("source_branch", ref)
+		apiURL = fmt.Sprintf("%s/projects/%s/merge_requests?%s",
+			gitlabAPIBase, projectPath, query.Encode())
+	}
+
+	resp, err := c.doRequest(ctx, "GET", apiURL, nil, account.Token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && byNumber {
+		return nil, fmt.Errorf("merge request !%d not found", number)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get MR: %s", resp.Status)
+	}
+
+	type glMergeRequest struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		WebURL string `json:"web_url"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+	}
+
+	var mr glMergeRequest
+	if byNumber {
+		if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
+			return nil, err
+		}
+	} else {
+		var glMRs []glMergeRequest
+		if err := json.NewDecoder(resp.Body).Decode(&glMRs); err != nil {
+			return nil, err
+		}
+		if len(glMRs) == 0 {
+			return nil, nil
+		}
+		mr = glMRs[0]
+	}
+
+	return &PullRequest{
+		Number:     mr.IID,
+		Title:      mr.Title,
+		URL:        mr.WebURL,
+		Author:     mr.Author.Username,
+		Branch:     mr.SourceBranch,
+		BaseBranch: mr.TargetBranch,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package branch
+
+import (
+	"github.com/pavlovic265/265-gt/constants"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type abortCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewAbortCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) abortCommand {
+	return abortCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc abortCommand) Command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "abort",
+		Short: "Abort the in-progress gt operation and roll it back",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			rebasing := svc.gitHelper.IsRebaseInProgress()
+
+			operation, err := svc.gitHelper.GetPendingOperation()
+			if err != nil {
+				return log.Error("failed to read pending operation", err)
+			}
+
+			if !rebasing && operation == nil {
+				return log.ErrorMsg("no gt operation or rebase in progress")
+			}
+
+			// Only a rebase gt paused, with no branch moved since, is rolled
+			// back; anything else is a plain `git rebase --abort`.
+			owned := svc.gitHelper.IsPausedOperation(operation)
+
+			if rebasing {
+				if err := svc.runner.Git("rebase", "--abort"); err != nil {
+					return log.Error("failed to abort rebase", err)
+				}
+			}
+
+			if owned {
+				if err := svc.gitHelper.RestoreSnapshot(operation); err != nil {
+					return log.Error("failed to roll back branches", err)
+				}
+				// Nothing is left to undo once the operation is rolled back.
+				if err := svc.gitHelper.DeleteOperation(operation.ID); err != nil {
+					return log.Error("failed to update operation log", err)
+				}
+			}
+
+			_ = svc.gitHelper.DeletePending(constants.ParentBranch)
+			_ = svc.gitHelper.DeletePending(constants.ChildBranch)
+			if err := svc.gitHelper.DiscardOperation(); err != nil {
+				return log.Error("failed to clear pending operation", err)
+			}
+
+			switch {
+			case owned && operation.Branch != "":
+				log.Successf("Aborted %s, back on %s", operation.Operation, operation.Branch)
+			case owned:
+				log.Successf("Aborted %s", operation.Operation)
+			case operation != nil:
+				log.Warningf("Cleared the unfinished %s without rolling it back: it was not paused by gt "+
+					"or branches changed since; run `gt undo --force` to roll it back", operation.Operation)
+			}
+			if rebasing && !owned {
+				log.Success("Rebase aborted")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package branch_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/constants"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/pavlovic265/265-gt/oplog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	abortCmd := branch.NewAbortCommand(mockRunner, mockGitHelper)
+	cmd := abortCmd.Command()
+
+	assert.Equal(t, "abort", cmd.Use)
+	assert.Equal(t, "Abort the in-progress gt operation and roll it back", cmd.Short)
+}
+
+func TestAbortCommand_RunE_RollsBackOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	snapshot := &oplog.Snapshot{
+		ID:        "1-restack",
+		Operation: "restack",
+		Branch:    "feature-a",
+		Refs:      map[string]string{"feature-a": "abc123", "feature-b": "def456"},
+	}
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(true)
+	mockGitHelper.EXPECT().GetPendingOperation().Return(snapshot, nil)
+	mockGitHelper.EXPECT().IsPausedOperation(snapshot).Return(true)
+	mockRunner.EXPECT().Git("rebase", "--abort").Return(nil)
+	mockGitHelper.EXPECT().RestoreSnapshot(snapshot).Return(nil)
+	mockGitHelper.EXPECT().DeleteOperation("1-restack").Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ParentBranch).Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ChildBranch).Return(nil)
+	mockGitHelper.EXPECT().DiscardOperation().Return(nil)
+
+	cmd := branch.NewAbortCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestAbortCommand_RunE_ForeignRebaseKeepsBranches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	// A stale snapshot left by a failed sync must not be restored over a
+	// plain `git rebase` started later.
+	snapshot := &oplog.Snapshot{
+		Operation: "sync",
+		Branch:    "main",
+		Refs:      map[string]string{"main": "abc123", "a": "def456"},
+	}
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(true)
+	mockGitHelper.EXPECT().GetPendingOperation().Return(snapshot, nil)
+	mockGitHelper.EXPECT().IsPausedOperation(snapshot).Return(false)
+	mockRunner.EXPECT().Git("rebase", "--abort").Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ParentBranch).Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ChildBranch).Return(nil)
+	mockGitHelper.EXPECT().DiscardOperation().Return(nil)
+
+	cmd := branch.NewAbortCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestAbortCommand_RunE_NothingInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetPendingOperation().Return(nil, nil)
+
+	cmd := branch.NewAbortCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Error(t, cmd.RunE(cmd, nil))
+}
@@ -0,0 +1,87 @@
+package branch
+
+import (
+	"context"
+	"fmt"
+
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type bottomCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewBottomCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) bottomCommand {
+	return bottomCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc bottomCommand) Command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bottom",
+		Short: "move to the first branch above trunk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			currentBranch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			if svc.gitHelper.IsProtectedBranch(cmd.Context(), currentBranch) {
+				return log.ErrorMsg("cannot move to bottom: current branch is not in a stack")
+			}
+
+			target, err := svc.descend(cmd.Context(), currentBranch)
+			if err != nil {
+				return err
+			}
+
+			if target == currentBranch {
+				log.Infof("Already at the bottom of the stack on '%s'", currentBranch)
+				return nil
+			}
+
+			if err := svc.runner.Git("checkout", target); err != nil {
+				return log.Error("failed to checkout branch", err)
+			}
+
+			log.Successf("Moved down to branch '%s'", target)
+			return nil
+		},
+	}
+}
+
+// descend follows recorded parents from branch down to the last one above
+// trunk. It stops early at a parent that no longer exists, and fails instead
+// of looping when the recorded parents form a cycle.
+func (svc bottomCommand) descend(ctx context.Context, branch string) (string, error) {
+	visited := map[string]bool{branch: true}
+	for {
+		parent, err := svc.gitHelper.GetParent(branch)
+		if err != nil || parent == "" || svc.gitHelper.IsProtectedBranch(ctx, parent) {
+			return branch, nil
+		}
+		if visited[parent] {
+			return "", log.ErrorMsg(fmt.Sprintf(
+				"parent cycle detected at '%s'; run `gt fsck --fix` to repair it", parent))
+		}
+		if _, err := svc.gitHelper.GetRevision("refs/heads/" + parent); err != nil {
+			log.Warningf("Stopping at '%s': its parent '%s' no longer exists; run `gt fsck --fix`", branch, parent)
+			return branch, nil
+		}
+		visited[parent] = true
+		branch = parent
+	}
+}
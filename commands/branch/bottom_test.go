@@ -0,0 +1,113 @@
+package branch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBottomCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := branch.NewBottomCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "bottom", cmd.Use)
+	assert.Equal(t, "move to the first branch above trunk", cmd.Short)
+}
+
+func TestBottomCommand_RunE_StopsAboveTrunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-c", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-c").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-c").Return("feature-b", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-b").Return(false)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("main", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "main").Return(true)
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+
+	cmd := branch.NewBottomCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestBottomCommand_RunE_StopsAtMissingParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-c", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-c").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-c").Return("feature-b", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-b").Return(false)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("gone", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "gone").Return(false)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/gone").Return("", errors.New("not found"))
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+
+	cmd := branch.NewBottomCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestBottomCommand_RunE_ParentCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false).Times(2)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("feature-b", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-b").Return(false)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+
+	cmd := branch.NewBottomCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parent cycle detected at 'feature-a'")
+}
+
+func TestBottomCommand_RunE_OnTrunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "main").Return(true)
+
+	cmd := branch.NewBottomCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not in a stack")
+}
@@ -100,6 +100,11 @@ func (svc cleanCommand) cleanBranches(ctx context.Context) error {
 		return nil
 	}
 
+	if err := svc.gitHelper.BeginOperation("clean"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
 	deletedCount := 0
 	skippedCount := 0
 	for _, branch := range branches {
@@ -124,6 +129,10 @@ func (svc cleanCommand) cleanBranches(ctx context.Context) error {
 		}
 	}
 
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+
 	fmt.Println()
 	if deletedCount > 0 {
 		log.Successf("Deleted %d branches", deletedCount)
@@ -208,6 +217,9 @@ func (svc cleanCommand) performDelete(branch, parent string, children []string)
 		}
 		fmt.Printf("   → Relinked %d children to %s\n", len(children), branchStyle.Render(parent))
 	}
+	if parent != "" {
+		_ = svc.gitHelper.DeleteParent(branch)
+	}
 
 	fmt.Printf("   ")
 	log.Success(output)
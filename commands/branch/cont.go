@@ -1,6 +1,8 @@
 package branch
 
 import (
+	"fmt"
+
 	"github.com/pavlovic265/265-gt/constants"
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
@@ -39,6 +41,7 @@ func (svc contCommand) Command() *cobra.Command {
 			if svc.gitHelper.IsRebaseInProgress() {
 				return nil
 			}
+			defer svc.gitHelper.SettleOperation()
 
 			parent, pErr := svc.gitHelper.GetPending(constants.ParentBranch)
 			child, cErr := svc.gitHelper.GetPending(constants.ChildBranch)
@@ -48,6 +51,12 @@ func (svc contCommand) Command() *cobra.Command {
 						return log.Error("failed to set parent branch relationship", err)
 					}
 
+					if revision, err := svc.gitHelper.GetRevision(parent); err == nil {
+						if err := svc.gitHelper.SetParentRevision(child, revision); err != nil {
+							return log.Error("failed to record parent revision", err)
+						}
+					}
+
 					_ = svc.gitHelper.DeletePending(constants.ParentBranch)
 					_ = svc.gitHelper.DeletePending(constants.ChildBranch)
 
@@ -55,7 +64,53 @@ func (svc contCommand) Command() *cobra.Command {
 				}
 			}
 
+			queue, err := svc.gitHelper.GetPendingQueue()
+			if err == nil && len(queue) > 0 {
+				log.Infof("Continuing restack of %d remaining branches", len(queue))
+				if _, err := svc.gitHelper.RestackBranches(queue); err != nil {
+					return err
+				}
+				log.Success("Restack completed")
+			}
+
+			chain, err := svc.gitHelper.GetPendingChain()
+			if err == nil && len(chain) > 0 {
+				log.Infof("Continuing restack of %d remaining branches", len(chain))
+				if _, err := svc.gitHelper.RestackChain(chain); err != nil {
+					return err
+				}
+				log.Success("Restack completed")
+			}
+
+			if err := svc.returnToStart(); err != nil {
+				return err
+			}
+
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
+			}
 			return nil
 		},
 	}
 }
+
+// returnToStart checks out the branch the paused operation started on, as the
+// operation itself would have once its restack finished. A branch the
+// operation removed, such as a folded one, is left alone.
+func (svc contCommand) returnToStart() error {
+	operation, err := svc.gitHelper.GetPendingOperation()
+	if err != nil || operation == nil || operation.Branch == "" {
+		return nil
+	}
+	if current, err := svc.gitHelper.GetCurrentBranch(); err == nil && current == operation.Branch {
+		return nil
+	}
+	if _, err := svc.gitHelper.GetRevision("refs/heads/" + operation.Branch); err != nil {
+		return nil
+	}
+
+	if err := svc.runner.Git("checkout", "-q", operation.Branch); err != nil {
+		return log.Error(fmt.Sprintf("failed to checkout %s", operation.Branch), err)
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package branch_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/constants"
 	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/pavlovic265/265-gt/oplog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,3 +39,67 @@ func TestNewContCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "cont", cmd.Use)
 }
+
+func TestContCommand_RunE_RecordsParentRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockRunner.EXPECT().Git("rebase", "--continue").Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetPending(constants.ParentBranch).Return("main", nil)
+	mockGitHelper.EXPECT().GetPending(constants.ChildBranch).Return("feature", nil)
+	mockGitHelper.EXPECT().SetParent("main", "feature").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("main").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature", "abc123").Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ParentBranch).Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ChildBranch).Return(nil)
+	mockGitHelper.EXPECT().GetPendingQueue().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingChain().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingOperation().Return(nil, nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+
+	cmd := branch.NewContCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestContCommand_RunE_ResumesPendingQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockRunner.EXPECT().Git("rebase", "--continue").Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetPending(constants.ParentBranch).Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetPending(constants.ChildBranch).Return("feature-b", nil)
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-b", "abc123").Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ParentBranch).Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ChildBranch).Return(nil)
+	mockGitHelper.EXPECT().GetPendingQueue().Return([]string{"feature-c", "feature-d"}, nil)
+	mockGitHelper.EXPECT().GetPendingChain().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-c", "feature-d"}).Return(2, nil)
+	mockGitHelper.EXPECT().GetPendingOperation().Return(&oplog.Snapshot{Operation: "restack", Branch: "feature-e"}, nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-d", nil)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-e").Return("def456", nil)
+	mockRunner.EXPECT().Git("checkout", "-q", "feature-e").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+
+	cmd := branch.NewContCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -1,29 +1,47 @@
 package branch
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/client"
+	"github.com/pavlovic265/265-gt/config"
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/branchname"
 	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/pavlovic265/265-gt/utils/timeutils"
 	"github.com/spf13/cobra"
 )
 
 type createCommand struct {
 	runner    runner.Runner
 	gitHelper helpers.GitHelper
+	cliClient client.CliClient
 }
 
 func NewCreateCommand(
 	runner runner.Runner,
 	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
 ) createCommand {
 	return createCommand{
 		runner:    runner,
 		gitHelper: gitHelper,
+		cliClient: cliClient,
 	}
 }
 
 func (svc createCommand) Command() *cobra.Command {
-	return &cobra.Command{
+	var (
+		insert  bool
+		message string
+		all     bool
+		patch   bool
+	)
+
+	cmd := &cobra.Command{
 		Use:     "create",
 		Aliases: []string{"c"},
 		Short:   "create branch",
@@ -32,10 +50,19 @@ func (svc createCommand) Command() *cobra.Command {
 				return err
 			}
 
-			if len(args) == 0 {
+			if (all || patch) && message == "" {
+				return log.ErrorMsg("--all and --patch need a commit message (-m)")
+			}
+
+			var branch string
+			switch {
+			case len(args) > 0:
+				branch = args[0]
+			case message != "":
+				branch = branchNameFromMessage(cmd.Context(), message)
+			default:
 				return log.ErrorMsg("branch name is required")
 			}
-			branch := args[0]
 
 			if err := svc.gitHelper.ValidateBranchName(branch); err != nil {
 				return log.Error("invalid branch name", err)
@@ -45,6 +72,25 @@ func (svc createCommand) Command() *cobra.Command {
 				return log.Error("failed to get current branch name", err)
 			}
 
+			if message != "" {
+				if err := svc.stage(all, patch); err != nil {
+					return err
+				}
+			}
+
+			var children []string
+			if insert {
+				if svc.gitHelper.IsRebaseInProgress() {
+					return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+				}
+				children = svc.gitHelper.GetChildren(parent)
+
+				if err := svc.gitHelper.BeginOperation("insert"); err != nil {
+					return log.Error("failed to record operation", err)
+				}
+				defer svc.gitHelper.SettleOperation()
+			}
+
 			if err := svc.runner.Git("checkout", "-b", branch); err != nil {
 				return log.Error("failed to create and checkout branch", err)
 			}
@@ -53,8 +99,147 @@ func (svc createCommand) Command() *cobra.Command {
 				return log.Error("failed to set parent branch relationship", err)
 			}
 
+			if revision, err := svc.gitHelper.GetRevision(parent); err == nil {
+				if err := svc.gitHelper.SetParentRevision(branch, revision); err != nil {
+					return log.Error("failed to record parent revision", err)
+				}
+			}
+
 			log.Successf("Branch '%s' created and switched to successfully", branch)
+
+			if message != "" {
+				if err := svc.commit(message); err != nil {
+					svc.rollback(parent, branch)
+					return err
+				}
+			}
+
+			if insert {
+				return svc.adoptChildren(cmd.Context(), branch, children)
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&insert, "insert", "i", false,
+		"Insert the new branch between the current branch and its children")
+	cmd.Flags().StringVarP(&message, "message", "m", "",
+		"Commit staged changes to the new branch; names the branch when no name is given")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Stage all changes before committing")
+	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Interactively pick hunks to stage before committing")
+
+	return cmd
+}
+
+// stage runs -a/-p staging and refuses when nothing ends up staged, so a
+// failing commit never leaves an empty branch behind.
+func (svc createCommand) stage(all, patch bool) error {
+	if all {
+		if err := svc.runner.Git("add", "-A"); err != nil {
+			return log.Error("failed to stage changes", err)
+		}
+	}
+	if patch {
+		if err := svc.runner.Git("add", "-p"); err != nil {
+			return log.Error("failed to stage changes", err)
+		}
+	}
+
+	staged, err := svc.runner.GitOutput("diff", "--cached", "--name-only")
+	if err != nil {
+		return log.Error("failed to read staged changes", err)
+	}
+	if strings.TrimSpace(staged) == "" {
+		return log.ErrorMsg("nothing staged to commit; stage changes or pass --all/--patch")
+	}
+	return nil
+}
+
+func (svc createCommand) commit(message string) error {
+	if err := svc.runner.Git("commit", "-m", message); err != nil {
+		return log.Error("failed to create commit", err)
+	}
+
+	log.Successf("Commit created: %s", message)
+	return nil
+}
+
+// rollback removes a branch whose commit failed, e.g. when a hook rejected it.
+// Staged changes stay in the index on parent.
+func (svc createCommand) rollback(parent, branch string) {
+	if err := svc.runner.Git("checkout", "-q", parent); err != nil {
+		log.Warningf("Could not return to %s; branch %s was kept", parent, branch)
+		return
+	}
+	if err := svc.runner.Git("branch", "-D", branch); err != nil {
+		log.Warningf("Could not delete branch %s", branch)
+		return
+	}
+	_ = svc.gitHelper.DeleteParent(branch)
+}
+
+// branchNameFromMessage applies the repository's branch_pattern to message,
+// filling {user} from the active account.
+func branchNameFromMessage(ctx context.Context, message string) string {
+	var pattern, user string
+	if cfg, ok := config.GetConfig(ctx); ok {
+		if cfg.Local != nil {
+			pattern = cfg.Local.BranchPattern
+		}
+		if cfg.Global != nil && cfg.Global.ActiveAccount != nil {
+			user = cfg.Global.ActiveAccount.User
+		}
+	}
+	return branchname.FromMessage(pattern, message, user, timeutils.Now())
+}
+
+// adoptChildren re-parents children onto the inserted branch, restacks them
+// and points their open pull requests at it. The branch is pushed first, as a
+// pull request can only target a branch that exists on origin.
+func (svc createCommand) adoptChildren(ctx context.Context, branch string, children []string) error {
+	for _, child := range children {
+		if err := svc.gitHelper.SetParent(branch, child); err != nil {
+			return log.Error("failed to set parent branch relationship", err)
+		}
+	}
+
+	if len(children) > 0 {
+		if _, err := svc.gitHelper.RestackBranches(children); err != nil {
+			return log.Error("failed to restack children; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout branch %s", branch), err)
+		}
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+	log.Successf("Moved %d child branches onto %s", len(children), branch)
+
+	var pushed []string
+	for _, child := range children {
+		if _, err := svc.gitHelper.GetRevision("refs/remotes/origin/" + child); err == nil {
+			pushed = append(pushed, child)
+		}
+	}
+	if len(pushed) == 0 {
+		return nil
+	}
+
+	if err := svc.runner.Git("push", "origin", branch); err != nil {
+		log.Warningf("failed to push %s, so the pull requests of %s still target the old parent; "+
+			"run `gt submit-stack` to update them: %v", branch, strings.Join(pushed, ", "), err)
+		return nil
+	}
+	for _, child := range pushed {
+		if err := svc.cliClient.UpdatePullRequestBaseBranch(ctx, child); err != nil {
+			log.Warningf("failed to update pull request base for %s: %v", child, err)
+		}
+	}
+	return nil
 }
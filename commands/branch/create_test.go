@@ -1,11 +1,15 @@
 package branch_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/config"
 	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,13 +19,19 @@ func TestCreateCommand_Command(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
 
-	createCmd := branch.NewCreateCommand(mockRunner, mockGitHelper)
+	createCmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient)
 	cmd := createCmd.Command()
 
 	assert.Equal(t, "create", cmd.Use)
 	assert.Equal(t, []string{"c"}, cmd.Aliases)
 	assert.Equal(t, "create branch", cmd.Short)
+
+	insertFlag := cmd.Flags().Lookup("insert")
+	assert.NotNil(t, insertFlag)
+	assert.Equal(t, "i", insertFlag.Shorthand)
+	assert.Equal(t, "false", insertFlag.DefValue)
 }
 
 func TestNewCreateCommand(t *testing.T) {
@@ -30,10 +40,190 @@ func TestNewCreateCommand(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
 
-	createCmd := branch.NewCreateCommand(mockRunner, mockGitHelper)
+	createCmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient)
 	cmd := createCmd.Command()
 
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "create", cmd.Use)
 }
+
+func TestCreateCommand_RunE_InsertAdoptsChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().ValidateBranchName("feature-mid").Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b", "feature-c"})
+	mockGitHelper.EXPECT().BeginOperation("insert").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("checkout", "-b", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-mid", "abc123").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-mid", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-mid", "feature-c").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-b", "feature-c"}).Return(2, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/feature-b").Return("def456", nil)
+	mockGitHelper.EXPECT().
+		GetRevision("refs/remotes/origin/feature-c").
+		Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+	mockRunner.EXPECT().Git("push", "origin", "feature-mid").Return(nil)
+	mockCliClient.EXPECT().UpdatePullRequestBaseBranch(gomock.Any(), "feature-b").Return(nil)
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("insert", "true"))
+
+	if err := cmd.RunE(cmd, []string{"feature-mid"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestCreateCommand_RunE_InsertPushFailureKeepsPullRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().ValidateBranchName("feature-mid").Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().BeginOperation("insert").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("checkout", "-b", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-mid", "abc123").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-mid", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-b"}).Return(1, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-mid").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/feature-b").Return("def456", nil)
+	// The pull request cannot target a branch origin does not have, so its
+	// base is left alone and only a warning is printed.
+	mockRunner.EXPECT().Git("push", "origin", "feature-mid").Return(errors.New("no origin"))
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("insert", "true"))
+
+	if err := cmd.RunE(cmd, []string{"feature-mid"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestCreateCommand_RunE_NameFromMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().ValidateBranchName("alice/add-login-form").Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	gomock.InOrder(
+		mockRunner.EXPECT().Git("add", "-A").Return(nil),
+		mockRunner.EXPECT().GitOutput("diff", "--cached", "--name-only").Return("login.go", nil),
+		mockRunner.EXPECT().Git("checkout", "-b", "alice/add-login-form").Return(nil),
+		mockRunner.EXPECT().Git("commit", "-m", "Add login form").Return(nil),
+	)
+	mockGitHelper.EXPECT().SetParent("main", "alice/add-login-form").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("main").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("alice/add-login-form", "abc123").Return(nil)
+
+	cfg := config.NewConfigContext(&config.GlobalConfigStruct{
+		ActiveAccount: &config.Account{User: "alice"},
+	}, &config.LocalConfigStruct{BranchPattern: "{user}/{slug}"})
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	cmd.SetContext(config.WithConfig(context.Background(), cfg))
+	assert.NoError(t, cmd.Flags().Set("message", "Add login form"))
+	assert.NoError(t, cmd.Flags().Set("all", "true"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestCreateCommand_RunE_MessageWithNothingStaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().ValidateBranchName("feature").Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockRunner.EXPECT().GitOutput("diff", "--cached", "--name-only").Return("", nil)
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("message", "Add feature"))
+
+	err := cmd.RunE(cmd, []string{"feature"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing staged")
+}
+
+func TestCreateCommand_RunE_FailedCommitRemovesBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().ValidateBranchName("feature").Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().SetParent("main", "feature").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("main").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature", "abc123").Return(nil)
+	gomock.InOrder(
+		mockRunner.EXPECT().GitOutput("diff", "--cached", "--name-only").Return("main.go", nil),
+		mockRunner.EXPECT().Git("checkout", "-b", "feature").Return(nil),
+		mockRunner.EXPECT().Git("commit", "-m", "Add feature").Return(errors.New("hook failed")),
+		mockRunner.EXPECT().Git("checkout", "-q", "main").Return(nil),
+		mockRunner.EXPECT().Git("branch", "-D", "feature").Return(nil),
+	)
+	mockGitHelper.EXPECT().DeleteParent("feature").Return(nil)
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("message", "Add feature"))
+
+	err := cmd.RunE(cmd, []string{"feature"})
+	assert.Error(t, err)
+}
+
+func TestCreateCommand_RunE_AllRequiresMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+
+	cmd := branch.NewCreateCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("all", "true"))
+
+	err := cmd.RunE(cmd, []string{"feature"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "need a commit message")
+}
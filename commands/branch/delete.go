@@ -88,6 +88,11 @@ func (svc deleteCommand) deleteBranch(
 	}
 	branchChildren := svc.gitHelper.GetChildren(branch)
 
+	if err := svc.gitHelper.BeginOperation("delete"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
 	if err := svc.runner.Git("branch", "-D", branch); err != nil {
 		return log.Error("failed to delete branch", err)
 	}
@@ -96,6 +101,13 @@ func (svc deleteCommand) deleteBranch(
 	if err != nil {
 		return log.Error("failed to update branch relationships", err)
 	}
+	if parent != "" {
+		_ = svc.gitHelper.DeleteParent(branch)
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
 
 	log.Successf("Branch '%s' deleted successfully", branch)
 	return nil
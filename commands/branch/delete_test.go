@@ -1,6 +1,8 @@
 package branch_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -38,3 +40,26 @@ func TestNewDeleteCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "delete", cmd.Use)
 }
+
+func TestDeleteCommand_RunE_FailureSettlesOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature").Return("main", nil)
+	mockGitHelper.EXPECT().GetChildren("feature").Return(nil)
+	mockGitHelper.EXPECT().BeginOperation("delete").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("branch", "-D", "feature").Return(errors.New("exit status 1"))
+
+	cmd := branch.NewDeleteCommand(mockRunner, mockGitHelper).Command()
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, []string{"feature"})
+
+	assert.Error(t, err)
+}
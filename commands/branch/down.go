@@ -1,6 +1,8 @@
 package branch
 
 import (
+	"fmt"
+
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/utils/log"
@@ -26,30 +28,52 @@ func (svc downCommand) Command() *cobra.Command {
 	return &cobra.Command{
 		Use:   "down",
 		Short: "move to branch down in stack",
+		Long:  "Move down to the parent branch, or N levels with `gt down N`.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
 				return err
 			}
 
+			steps, err := parseSteps(args)
+			if err != nil {
+				return err
+			}
+
 			currentBranch, err := svc.gitHelper.GetCurrentBranch()
 			if err != nil {
 				return log.Error("failed to get current branch name", err)
 			}
 
-			parent, err := svc.gitHelper.GetParent(currentBranch)
-			if err != nil {
-				return log.Error("failed to get parent branch", err)
+			target := currentBranch
+			visited := map[string]bool{target: true}
+			moved := 0
+			for moved < steps {
+				parent, err := svc.gitHelper.GetParent(target)
+				if err != nil || parent == "" || parent == target {
+					break
+				}
+				if visited[parent] {
+					return log.ErrorMsg(fmt.Sprintf(
+						"parent cycle detected at '%s'; run `gt fsck --fix` to repair it", parent))
+				}
+				visited[parent] = true
+				target = parent
+				moved++
 			}
 
-			if parent == "" {
+			if moved == 0 {
 				return log.ErrorMsg("cannot move down: no parent branch available")
 			}
+			if moved < steps {
+				log.Infof("Reached the bottom of the stack after %d of %d steps", moved, steps)
+			}
 
-			if err := svc.runner.Git("checkout", parent); err != nil {
+			if err := svc.runner.Git("checkout", target); err != nil {
 				return log.Error("failed to checkout parent branch", err)
 			}
 
-			log.Successf("Moved down to branch '%s'", parent)
+			log.Successf("Moved down to branch '%s'", target)
 			return nil
 		},
 	}
@@ -1,6 +1,7 @@
 package branch_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -36,3 +37,41 @@ func TestNewDownCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "down", cmd.Use)
 }
+
+func TestDownCommand_RunE_MovesSeveralLevels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-c", nil)
+	mockGitHelper.EXPECT().GetParent("feature-c").Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+
+	cmd := branch.NewDownCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, []string{"2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestDownCommand_RunE_NoParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().GetParent("main").Return("", errors.New("no parent recorded for branch main"))
+
+	cmd := branch.NewDownCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no parent branch available")
+}
@@ -0,0 +1,166 @@
+package branch
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavlovic265/265-gt/client"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/components"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type foldCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+	cliClient client.CliClient
+}
+
+func NewFoldCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
+) foldCommand {
+	return foldCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+		cliClient: cliClient,
+	}
+}
+
+func (svc foldCommand) Command() *cobra.Command {
+	var squash bool
+
+	cmd := &cobra.Command{
+		Use:   "fold",
+		Short: "Fold the current branch into its parent",
+		Long: "Merge the current branch's commits into its parent, delete the branch and move its " +
+			"children onto the parent. With --squash the commits are combined into one.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			ctx := cmd.Context()
+			branch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			if svc.gitHelper.IsProtectedBranch(ctx, branch) {
+				return log.ErrorMsg("cannot fold protected branch: " + branch)
+			}
+
+			parent, err := svc.gitHelper.GetParent(branch)
+			if err != nil || parent == "" {
+				return log.ErrorMsg(fmt.Sprintf("branch %s has no parent; run `gt track` first", branch))
+			}
+
+			if svc.gitHelper.IsProtectedBranch(ctx, parent) {
+				return log.ErrorMsg(fmt.Sprintf("cannot fold into protected branch %s; open a pull request instead", parent))
+			}
+
+			if svc.gitHelper.NeedsRestack(branch, parent) {
+				return log.ErrorMsg(fmt.Sprintf("branch %s is behind %s; restack it before folding", branch, parent))
+			}
+
+			return svc.fold(ctx, branch, parent, squash)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&squash, "squash", "s", false, "Squash the branch's commits into a single commit on the parent")
+
+	return cmd
+}
+
+func (svc foldCommand) fold(ctx context.Context, branch, parent string, squash bool) error {
+	tip, err := svc.gitHelper.GetRevision(branch)
+	if err != nil {
+		return log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
+	}
+	hasOpenPR := hasOpenPullRequest(ctx, svc.gitHelper, svc.cliClient, branch)
+	children := svc.gitHelper.GetChildren(branch)
+
+	if err := svc.gitHelper.BeginOperation("fold"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
+	if err := svc.runner.Git("checkout", parent); err != nil {
+		return log.Error(fmt.Sprintf("failed to checkout branch %s", parent), err)
+	}
+
+	if squash {
+		if err := svc.runner.Git("merge", "--squash", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to squash %s into %s", branch, parent), err)
+		}
+		if err := svc.runner.Git("commit", "--no-edit"); err != nil {
+			return log.Error(fmt.Sprintf("failed to commit squashed changes of %s", branch), err)
+		}
+	} else {
+		if err := svc.runner.Git("merge", "--ff-only", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to fast-forward %s to %s", parent, branch), err)
+		}
+	}
+
+	// Children are still based on the folded branch's commits; remember where
+	// so a squash can replay only their own commits onto the parent.
+	if err := svc.gitHelper.PinChildren(branch, tip); err != nil {
+		return log.Error("failed to record parent revision", err)
+	}
+
+	if err := svc.runner.Git("branch", "-D", branch); err != nil {
+		return log.Error("failed to delete branch", err)
+	}
+	if err := svc.gitHelper.RelinkParentChildren(parent, children); err != nil {
+		return log.Error("failed to update branch relationships", err)
+	}
+	_ = svc.gitHelper.DeleteParent(branch)
+
+	log.Successf("Folded %s into %s", branch, parent)
+
+	if squash && len(children) > 0 {
+		if _, err := svc.gitHelper.RestackBranches(children); err != nil {
+			return log.Error("failed to restack children; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", parent); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout branch %s", parent), err)
+		}
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+
+	if hasOpenPR {
+		svc.offerToClosePullRequest(ctx, branch)
+	}
+
+	return nil
+}
+
+func (svc foldCommand) offerToClosePullRequest(ctx context.Context, branch string) {
+	program := tea.NewProgram(components.NewYesNoPrompt(
+		fmt.Sprintf("Branch %s has an open pull request. Close it?", branch)))
+	m, err := program.Run()
+	if err != nil {
+		log.Warningf("failed to display prompt: %v", err)
+		return
+	}
+	if model, ok := m.(components.YesNoPrompt); !ok || !model.IsYes() {
+		return
+	}
+
+	if err := svc.cliClient.ClosePullRequest(ctx, branch); err != nil {
+		log.Warningf("failed to close pull request for %s: %v", branch, err)
+		return
+	}
+	log.Successf("Closed pull request for %s", branch)
+}
@@ -0,0 +1,162 @@
+package branch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	cmd := branch.NewFoldCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	assert.Equal(t, "fold", cmd.Use)
+	assert.Equal(t, "Fold the current branch into its parent", cmd.Short)
+
+	squashFlag := cmd.Flags().Lookup("squash")
+	assert.NotNil(t, squashFlag)
+	assert.Equal(t, "s", squashFlag.Shorthand)
+	assert.Equal(t, "false", squashFlag.DefValue)
+}
+
+func expectFoldPreamble(mockGitHelper *mocks.MockGitHelper) {
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-b").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+}
+
+func TestFoldCommand_RunE_RefusesProtectedParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectFoldPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(true)
+
+	cmd := branch.NewFoldCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot fold into protected branch")
+}
+
+func TestFoldCommand_RunE_FastForwardsParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectFoldPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().NeedsRestack("feature-b", "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("tip", nil)
+	mockGitHelper.EXPECT().
+		GetRevision("refs/remotes/origin/feature-b").
+		Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().BeginOperation("fold").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+	mockRunner.EXPECT().Git("merge", "--ff-only", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-b", "tip").Return(nil)
+	mockRunner.EXPECT().Git("branch", "-D", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().RelinkParentChildren("feature-a", []string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().DeleteParent("feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewFoldCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestFoldCommand_RunE_SquashRestacksChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectFoldPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().NeedsRestack("feature-b", "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("tip", nil)
+	mockGitHelper.EXPECT().
+		GetRevision("refs/remotes/origin/feature-b").
+		Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().BeginOperation("fold").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+	mockRunner.EXPECT().Git("merge", "--squash", "feature-b").Return(nil)
+	mockRunner.EXPECT().Git("commit", "--no-edit").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-b", "tip").Return(nil)
+	mockRunner.EXPECT().Git("branch", "-D", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().RelinkParentChildren("feature-a", []string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().DeleteParent("feature-b").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-c"}).Return(1, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewFoldCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("squash", "true"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestFoldCommand_RunE_RestackConflictLeavesOperationOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectFoldPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().NeedsRestack("feature-b", "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("tip", nil)
+	mockGitHelper.EXPECT().
+		GetRevision("refs/remotes/origin/feature-b").
+		Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().BeginOperation("fold").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+	mockRunner.EXPECT().Git("merge", "--squash", "feature-b").Return(nil)
+	mockRunner.EXPECT().Git("commit", "--no-edit").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-b", "tip").Return(nil)
+	mockRunner.EXPECT().Git("branch", "-D", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().RelinkParentChildren("feature-a", []string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().DeleteParent("feature-b").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-c"}).Return(0, errors.New("conflict"))
+
+	cmd := branch.NewFoldCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("squash", "true"))
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "gt cont")
+}
@@ -1,6 +1,8 @@
 package branch
 
 import (
+	"fmt"
+
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/ui/components"
@@ -24,10 +26,14 @@ func NewMoveCommand(
 }
 
 func (svc moveCommand) Command() *cobra.Command {
-	return &cobra.Command{
+	var only bool
+
+	cmd := &cobra.Command{
 		Use:     "move",
 		Aliases: []string{"mo"},
 		Short:   "rebase branch onto other branch",
+		Long: "Rebase the current branch onto another branch and restack its descendants on top. " +
+			"With --only, just the branch moves and its children are re-parented onto its old parent.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
 				return err
@@ -42,10 +48,24 @@ func (svc moveCommand) Command() *cobra.Command {
 				return log.Error("failed to get current branch name", err)
 			}
 
+			oldParent, _ := svc.gitHelper.GetParent(branch)
+			if only && oldParent == "" {
+				return log.ErrorMsg(fmt.Sprintf("branch %s has no parent to leave its children on; run `gt track` first", branch))
+			}
+
+			// Without --only the subtree moves along, so it cannot be a target.
+			excluded := map[string]bool{branch: true}
+			if !only {
+				for _, descendant := range svc.descendants(branch) {
+					excluded[descendant] = true
+				}
+			}
+
+			var parent string
 			if len(args) > 0 {
-				parent := args[0]
-				if err := svc.gitHelper.RebaseBranch(branch, parent); err != nil {
-					return err
+				parent = args[0]
+				if excluded[parent] {
+					return log.ErrorMsg(fmt.Sprintf("cannot move %s onto itself or its descendant %s", branch, parent))
 				}
 			} else {
 				branches, err := svc.gitHelper.GetBranches()
@@ -53,24 +73,103 @@ func (svc moveCommand) Command() *cobra.Command {
 					return log.Error("failed to get branch list", err)
 				}
 
-				if err := svc.rebaseBranch(branch, branches); err != nil {
-					return err
+				var choices []string
+				for _, b := range branches {
+					if !excluded[b] {
+						choices = append(choices, b)
+					}
+				}
+
+				parent, err = components.SelectString(choices)
+				if err != nil {
+					return log.Error("failed to display branch selection menu", err)
+				}
+				if parent == "" {
+					return log.ErrorMsg("no target branch selected for rebase")
 				}
 			}
 
-			return nil
+			return svc.move(branch, parent, oldParent, only)
 		},
 	}
+
+	cmd.Flags().BoolVar(&only, "only", false, "Move just this branch and re-parent its children onto its old parent")
+
+	return cmd
 }
 
-func (svc moveCommand) rebaseBranch(branch string, choices []string) error {
-	selected, err := components.SelectString(choices)
+func (svc moveCommand) move(branch, parent, oldParent string, only bool) error {
+	tip, err := svc.gitHelper.GetRevision(branch)
 	if err != nil {
-		return log.Error("failed to display branch selection menu", err)
+		return log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
 	}
-	if selected == "" {
-		return log.ErrorMsg("no target branch selected for rebase")
+	children := svc.gitHelper.GetChildren(branch)
+
+	if err := svc.gitHelper.BeginOperation("move"); err != nil {
+		return log.Error("failed to record operation", err)
 	}
+	defer svc.gitHelper.SettleOperation()
 
-	return svc.gitHelper.RebaseBranch(branch, selected)
+	// Children are replayed from the branch's current tip, whether they follow
+	// it to the new parent or stay behind on the old one.
+	if err := svc.gitHelper.PinChildren(branch, tip); err != nil {
+		return log.Error("failed to record parent revision", err)
+	}
+
+	if only {
+		if err := svc.gitHelper.RelinkParentChildren(oldParent, children); err != nil {
+			return log.Error("failed to update branch relationships", err)
+		}
+	}
+
+	// Queued first, so `gt cont` restacks the children if the move stops on conflicts.
+	if len(children) > 0 {
+		if err := svc.gitHelper.SetPendingQueue(children); err != nil {
+			return log.Error("failed to save pending restack queue", err)
+		}
+	} else if err := svc.gitHelper.DeletePendingQueue(); err != nil {
+		return log.Error("failed to clear pending restack queue", err)
+	}
+
+	if err := svc.gitHelper.RebaseBranch(branch, parent); err != nil {
+		return err
+	}
+
+	if len(children) > 0 {
+		restacked, err := svc.gitHelper.RestackBranches(children)
+		if err != nil {
+			return log.Error("failed to restack children; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout branch %s", branch), err)
+		}
+
+		if only {
+			log.Successf("Moved %d child branches onto %s", len(children), oldParent)
+		} else {
+			log.Successf("Restacked %d descendants onto %s", restacked, branch)
+		}
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+	return nil
+}
+
+func (svc moveCommand) descendants(branch string) []string {
+	var result []string
+	queue := svc.gitHelper.GetChildren(branch)
+	seen := map[string]bool{branch: true}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		result = append(result, current)
+		queue = append(queue, svc.gitHelper.GetChildren(current)...)
+	}
+	return result
 }
@@ -37,3 +37,84 @@ func TestNewMoveCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "move", cmd.Use)
 }
+
+func TestMoveCommand_MovesSubtree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"}).Times(2)
+	mockGitHelper.EXPECT().GetChildren("feature-c").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("tip-b", nil)
+	mockGitHelper.EXPECT().BeginOperation("move").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-b", "tip-b").Return(nil)
+	mockGitHelper.EXPECT().SetPendingQueue([]string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().RebaseBranch("feature-b", "main").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-c"}).Return(1, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewMoveCommand(mockRunner, mockGitHelper).Command()
+	err := cmd.RunE(cmd, []string{"main"})
+
+	assert.NoError(t, err)
+}
+
+func TestMoveCommand_OnlyLeavesChildrenOnOldParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("tip-b", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().BeginOperation("move").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-b", "tip-b").Return(nil)
+	mockGitHelper.EXPECT().RelinkParentChildren("feature-a", []string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().SetPendingQueue([]string{"feature-c"}).Return(nil)
+	mockGitHelper.EXPECT().RebaseBranch("feature-b", "main").Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-c"}).Return(1, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewMoveCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("only", "true"))
+
+	err := cmd.RunE(cmd, []string{"main"})
+
+	assert.NoError(t, err)
+}
+
+func TestMoveCommand_RejectsDescendantTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().GetChildren("feature-c").Return(nil)
+
+	cmd := branch.NewMoveCommand(mockRunner, mockGitHelper).Command()
+	err := cmd.RunE(cmd, []string{"feature-c"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "descendant")
+}
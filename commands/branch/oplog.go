@@ -0,0 +1,75 @@
+package branch
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/theme"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	operationStyle = lipgloss.NewStyle().
+			Foreground(theme.Cyan).
+			Bold(true)
+
+	timeStyle = lipgloss.NewStyle().
+			Foreground(theme.BrightBlack)
+)
+
+type oplogCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewOplogCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) oplogCommand {
+	return oplogCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc oplogCommand) Command() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "oplog",
+		Short: "List recent gt operations that can be undone",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			operations, err := svc.gitHelper.GetOperationLog()
+			if err != nil {
+				return log.Error("failed to read operation log", err)
+			}
+			if len(operations) == 0 {
+				log.Info("No operations recorded")
+				return nil
+			}
+
+			if limit > 0 && len(operations) > limit {
+				operations = operations[:limit]
+			}
+			for i, operation := range operations {
+				fmt.Printf("%2d  %s  %s  %s\n",
+					i+1,
+					timeStyle.Render(operation.CreatedAt.Format("2006-01-02 15:04:05")),
+					operationStyle.Render(fmt.Sprintf("%-12s", operation.Operation)),
+					branchStyle.Render(operation.Branch))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Number of operations to show (0 for all)")
+
+	return cmd
+}
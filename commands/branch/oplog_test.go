@@ -0,0 +1,50 @@
+package branch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/pavlovic265/265-gt/oplog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOplogCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	oplogCmd := branch.NewOplogCommand(mockRunner, mockGitHelper)
+	cmd := oplogCmd.Command()
+
+	assert.Equal(t, "oplog", cmd.Use)
+	assert.Equal(t, "List recent gt operations that can be undone", cmd.Short)
+
+	limitFlag := cmd.Flags().Lookup("limit")
+	assert.NotNil(t, limitFlag)
+	assert.Equal(t, "n", limitFlag.Shorthand)
+	assert.Equal(t, "10", limitFlag.DefValue)
+}
+
+func TestOplogCommand_RunE_ListsOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetOperationLog().Return([]*oplog.Snapshot{
+		{ID: "2-delete", Operation: "delete", Branch: "main", CreatedAt: time.Now()},
+	}, nil)
+
+	cmd := branch.NewOplogCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package branch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/client"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/components"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type renameCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+	cliClient client.CliClient
+}
+
+func NewRenameCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
+) renameCommand {
+	return renameCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+		cliClient: cliClient,
+	}
+}
+
+func (svc renameCommand) Command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename [new-name]",
+		Short: "Rename the current branch and keep its stack links",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			ctx := cmd.Context()
+			branch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			if svc.gitHelper.IsProtectedBranch(ctx, branch) {
+				return log.ErrorMsg("cannot rename protected branch: " + branch)
+			}
+
+			newName, err := svc.newName(branch, args)
+			if err != nil {
+				return err
+			}
+
+			if err := svc.gitHelper.ValidateBranchName(newName); err != nil {
+				return log.Error("invalid branch name", err)
+			}
+
+			hasOpenPR := hasOpenPullRequest(ctx, svc.gitHelper, svc.cliClient, branch)
+
+			if err := svc.gitHelper.BeginOperation("rename"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
+
+			if err := svc.runner.Git("branch", "-m", branch, newName); err != nil {
+				return log.Error("failed to rename branch", err)
+			}
+
+			if err := svc.gitHelper.MoveBranchMetadata(branch, newName); err != nil {
+				return log.Error("failed to move branch metadata", err)
+			}
+
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
+			}
+
+			log.Successf("Branch '%s' renamed to '%s'", branch, newName)
+
+			if hasOpenPR {
+				svc.renameRemoteBranch(ctx, branch, newName)
+			}
+			return nil
+		},
+	}
+}
+
+func (svc renameCommand) newName(branch string, args []string) (string, error) {
+	var newName string
+	if len(args) > 0 {
+		newName = strings.TrimSpace(args[0])
+	} else {
+		input := components.NewBranchInput()
+		input.SetValue(branch)
+
+		name, err := components.PromptString(fmt.Sprintf("New name for '%s':", branch), input)
+		if err != nil {
+			return "", log.Error("failed to display branch name prompt", err)
+		}
+		newName = name
+	}
+
+	if newName == "" {
+		return "", log.ErrorMsg("branch name is required")
+	}
+	if newName == branch {
+		return "", log.ErrorMsg("new branch name is the same as the current one")
+	}
+	return newName, nil
+}
+
+// hasOpenPullRequest only asks the platform when the branch was pushed, so
+// renaming or folding a local-only branch works without an account.
+func hasOpenPullRequest(
+	ctx context.Context,
+	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
+	branch string,
+) bool {
+	if _, err := gitHelper.GetRevision("refs/remotes/origin/" + branch); err != nil {
+		return false
+	}
+
+	state, err := cliClient.GetPullRequestState(ctx, branch)
+	if err != nil {
+		log.Warningf("failed to check pull request for %s: %v", branch, err)
+		return false
+	}
+	return state == client.PullRequestStateOpen
+}
+
+func (svc renameCommand) renameRemoteBranch(ctx context.Context, branch, newName string) {
+	if err := svc.cliClient.RenameBranch(ctx, branch, newName); err != nil {
+		log.Warningf("local branch renamed, but the remote branch and pull request still use '%s': %v", branch, err)
+		return
+	}
+
+	if err := svc.runner.Git("fetch", "--prune", "origin"); err != nil {
+		log.Warningf("failed to fetch origin: %v", err)
+		return
+	}
+	if err := svc.runner.Git("branch", "--set-upstream-to=origin/"+newName, newName); err != nil {
+		log.Warningf("failed to set upstream of %s: %v", newName, err)
+		return
+	}
+
+	log.Successf("Renamed remote branch and pull request head to '%s'", newName)
+}
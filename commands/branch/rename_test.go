@@ -0,0 +1,108 @@
+package branch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/client"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	renameCmd := branch.NewRenameCommand(mockRunner, mockGitHelper, mockCliClient)
+	cmd := renameCmd.Command()
+
+	assert.Equal(t, "rename [new-name]", cmd.Use)
+	assert.Equal(t, "Rename the current branch and keep its stack links", cmd.Short)
+}
+
+func TestRenameCommand_RunE_LocalBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().ValidateBranchName("feature-b").Return(nil)
+	mockGitHelper.EXPECT().
+		GetRevision("refs/remotes/origin/feature-a").
+		Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().BeginOperation("rename").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("branch", "-m", "feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().MoveBranchMetadata("feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewRenameCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	if err := cmd.RunE(cmd, []string{"feature-b"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRenameCommand_RunE_RenamesRemoteForOpenPR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().ValidateBranchName("feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/feature-a").Return("abc123", nil)
+	mockCliClient.EXPECT().
+		GetPullRequestState(gomock.Any(), "feature-a").
+		Return(client.PullRequestStateOpen, nil)
+	mockGitHelper.EXPECT().BeginOperation("rename").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("branch", "-m", "feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().MoveBranchMetadata("feature-a", "feature-b").Return(nil)
+	mockCliClient.EXPECT().RenameBranch(gomock.Any(), "feature-a", "feature-b").Return(nil)
+	mockRunner.EXPECT().Git("fetch", "--prune", "origin").Return(nil)
+	mockRunner.EXPECT().Git("branch", "--set-upstream-to=origin/feature-b", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := branch.NewRenameCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	if err := cmd.RunE(cmd, []string{"feature-b"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRenameCommand_RunE_RefusesProtectedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "main").Return(true)
+
+	cmd := branch.NewRenameCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+
+	assert.Error(t, cmd.RunE(cmd, []string{"trunk"}))
+}
@@ -0,0 +1,351 @@
+package branch
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/components"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type splitCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+type fileChange struct {
+	status string
+	path   string
+}
+
+func NewSplitCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) splitCommand {
+	return splitCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc splitCommand) Command() *cobra.Command {
+	var byFile bool
+
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split the current branch into a stack of branches",
+		Long: "Split the current branch into a stack of branches. Mark the last commit of each new " +
+			"branch; the remaining commits stay on the top piece, which keeps the children of the branch. " +
+			"With --by-file, changes are grouped by top-level directory instead of by commit.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			branch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			if svc.gitHelper.IsProtectedBranch(cmd.Context(), branch) {
+				return log.ErrorMsg("cannot split protected branch: " + branch)
+			}
+
+			parent, err := svc.gitHelper.GetParent(branch)
+			if err != nil || parent == "" {
+				return log.ErrorMsg(fmt.Sprintf("branch %s has no parent; run `gt track` first", branch))
+			}
+
+			if svc.gitHelper.NeedsRestack(branch, parent) {
+				return log.ErrorMsg(fmt.Sprintf("branch %s is behind %s; restack it before splitting", branch, parent))
+			}
+
+			if byFile {
+				return svc.splitByFile(branch, parent)
+			}
+			return svc.splitByCommit(branch, parent)
+		},
+	}
+
+	cmd.Flags().BoolVar(&byFile, "by-file", false, "Split changes by top-level directory instead of by commit")
+
+	return cmd
+}
+
+func (svc splitCommand) splitByCommit(branch, parent string) error {
+	commits, err := svc.gitHelper.GetCommits(parent, branch)
+	if err != nil {
+		return log.Error("failed to list commits", err)
+	}
+	slices.Reverse(commits)
+
+	if len(commits) < 2 {
+		return log.ErrorMsg(fmt.Sprintf("branch %s needs at least two commits to split by commit", branch))
+	}
+
+	marked, err := components.MultiSelect(
+		"Mark the last commit of each new branch (oldest first)", commits[:len(commits)-1])
+	if err != nil {
+		return log.Error("failed to display commit selection", err)
+	}
+	if len(marked) == 0 {
+		return log.ErrorMsg("no split points selected")
+	}
+
+	defaults := make([]string, 0, len(marked)+1)
+	for i := range marked {
+		defaults = append(defaults, fmt.Sprintf("%s-%d", branch, i+1))
+	}
+	names, err := svc.pieceNames(branch, append(defaults, branch))
+	if err != nil {
+		return err
+	}
+
+	if err := svc.gitHelper.BeginOperation("split"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
+	prev := parent
+	for i, index := range marked {
+		hash := strings.Fields(commits[index])[0]
+		if err := svc.runner.Git("branch", names[i], hash); err != nil {
+			return log.Error(fmt.Sprintf("failed to create branch %s", names[i]), err)
+		}
+		if err := svc.link(prev, names[i]); err != nil {
+			return err
+		}
+		prev = names[i]
+	}
+
+	if err := svc.finishTop(branch, names[len(names)-1], prev, names); err != nil {
+		return err
+	}
+	return svc.endOperation()
+}
+
+func (svc splitCommand) splitByFile(branch, parent string) error {
+	status, err := svc.runner.GitOutput("status", "--porcelain")
+	if err != nil {
+		return log.Error("failed to check working tree", err)
+	}
+	if status != "" {
+		return log.ErrorMsg("working tree has uncommitted changes; commit or stash them first")
+	}
+
+	base, err := svc.gitHelper.GetRevision(parent)
+	if err != nil {
+		return log.Error(fmt.Sprintf("failed to resolve %s", parent), err)
+	}
+	tip, err := svc.gitHelper.GetRevision(branch)
+	if err != nil {
+		return log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
+	}
+
+	output, err := svc.runner.GitOutput("diff", "--name-status", "--no-renames", base, tip)
+	if err != nil {
+		return log.Error("failed to list changed files", err)
+	}
+
+	groups, order := groupChangesByDirectory(output)
+	if len(order) < 2 {
+		return log.ErrorMsg("all changes are in one directory; nothing to split by file")
+	}
+
+	subject := branch
+	if commits, err := svc.gitHelper.GetCommits(parent, branch); err == nil && len(commits) > 0 {
+		if _, s, ok := strings.Cut(commits[len(commits)-1], " "); ok {
+			subject = s
+		}
+	}
+
+	defaults := make([]string, 0, len(order))
+	for _, group := range order[:len(order)-1] {
+		if group == "." {
+			group = "root"
+		}
+		defaults = append(defaults, branch+"-"+group)
+	}
+	names, err := svc.pieceNames(branch, append(defaults, branch))
+	if err != nil {
+		return err
+	}
+
+	if err := svc.gitHelper.BeginOperation("split"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
+	// The branch is rewritten, so children need to know which commits they
+	// were based on to be replayed onto the new top piece.
+	children := svc.gitHelper.GetChildren(branch)
+	if err := svc.gitHelper.PinChildren(branch, tip); err != nil {
+		return log.Error("failed to record parent revision", err)
+	}
+
+	prev := parent
+	for i, group := range order {
+		top := i == len(order)-1
+
+		// The top piece reuses the original branch so its ref, upstream and
+		// children stay in place; it is renamed afterwards if asked to.
+		checkout := []string{"checkout", "-q", "-b", names[i], prev}
+		if top {
+			checkout = []string{"checkout", "-q", "-B", branch, prev}
+		}
+		if err := svc.runner.Git(checkout...); err != nil {
+			return log.Error(fmt.Sprintf("failed to create branch %s", names[i]), err)
+		}
+
+		if err := svc.applyChanges(tip, groups[group]); err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("%s (%s)", subject, group)
+		if err := svc.runner.Git("commit", "-q", "-m", message); err != nil {
+			return log.Error(fmt.Sprintf("failed to commit changes for %s", group), err)
+		}
+
+		if !top {
+			if err := svc.link(prev, names[i]); err != nil {
+				return err
+			}
+			prev = names[i]
+		}
+	}
+
+	if err := svc.finishTop(branch, names[len(names)-1], prev, names); err != nil {
+		return err
+	}
+
+	if len(children) > 0 {
+		top := names[len(names)-1]
+		if _, err := svc.gitHelper.RestackBranches(svc.gitHelper.GetChildren(top)); err != nil {
+			return log.Error("failed to restack children; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", "-q", top); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout %s", top), err)
+		}
+	}
+	return svc.endOperation()
+}
+
+// endOperation is only reached on success; a failed split stays recorded as
+// the pending operation so `gt abort` can roll it back.
+func (svc splitCommand) endOperation() error {
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+	return nil
+}
+
+func (svc splitCommand) applyChanges(tip string, changes []fileChange) error {
+	for _, change := range changes {
+		var err error
+		if change.status == "D" {
+			err = svc.runner.Git("rm", "-q", "--", change.path)
+		} else {
+			err = svc.runner.Git("checkout", tip, "--", change.path)
+		}
+		if err != nil {
+			return log.Error(fmt.Sprintf("failed to apply %s", change.path), err)
+		}
+	}
+	return nil
+}
+
+// groupChangesByDirectory groups `git diff --name-status` output by top-level
+// directory; files in the repository root form the "." group.
+func groupChangesByDirectory(output string) (map[string][]fileChange, []string) {
+	groups := make(map[string][]fileChange)
+	for _, line := range strings.Split(output, "\n") {
+		status, path, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+
+		group := "."
+		if dir, _, nested := strings.Cut(path, "/"); nested {
+			group = dir
+		}
+		groups[group] = append(groups[group], fileChange{status: status, path: path})
+	}
+
+	order := make([]string, 0, len(groups))
+	for group := range groups {
+		order = append(order, group)
+	}
+	sort.Strings(order)
+	return groups, order
+}
+
+func (svc splitCommand) pieceNames(branch string, defaults []string) ([]string, error) {
+	names := make([]string, 0, len(defaults))
+	seen := make(map[string]bool)
+	for i, name := range defaults {
+		input := components.NewBranchInput()
+		input.SetValue(name)
+
+		name, err := components.PromptString(fmt.Sprintf("Name for piece %d of %d:", i+1, len(defaults)), input)
+		if err != nil {
+			return nil, log.Error("failed to display branch name prompt", err)
+		}
+		if name == "" {
+			return nil, log.ErrorMsg("split canceled")
+		}
+		if seen[name] {
+			return nil, log.ErrorMsg(fmt.Sprintf("branch name %s is used twice", name))
+		}
+		if err := svc.gitHelper.ValidateBranchName(name); err != nil {
+			return nil, log.Error("invalid branch name", err)
+		}
+		if _, err := svc.gitHelper.GetRevision("refs/heads/" + name); err == nil && name != branch {
+			return nil, log.ErrorMsg(fmt.Sprintf("branch %s already exists", name))
+		}
+
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (svc splitCommand) link(parent, child string) error {
+	if err := svc.gitHelper.SetParent(parent, child); err != nil {
+		return log.Error("failed to set parent branch relationship", err)
+	}
+	if revision, err := svc.gitHelper.GetRevision(parent); err == nil {
+		if err := svc.gitHelper.SetParentRevision(child, revision); err != nil {
+			return log.Error("failed to record parent revision", err)
+		}
+	}
+	return nil
+}
+
+// finishTop moves the original branch, and with it its children, to the top
+// piece's name and stacks it on the last new branch.
+func (svc splitCommand) finishTop(branch, top, prev string, names []string) error {
+	if top != branch {
+		if err := svc.runner.Git("branch", "-m", branch, top); err != nil {
+			return log.Error(fmt.Sprintf("failed to rename %s to %s", branch, top), err)
+		}
+		if err := svc.gitHelper.MoveBranchMetadata(branch, top); err != nil {
+			return log.Error("failed to move branch metadata", err)
+		}
+	}
+
+	if err := svc.link(prev, top); err != nil {
+		return err
+	}
+
+	log.Successf("Split %s into %d branches: %s", branch, len(names), strings.Join(names, " → "))
+	return nil
+}
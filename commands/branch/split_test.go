@@ -0,0 +1,114 @@
+package branch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := branch.NewSplitCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "split", cmd.Use)
+	assert.Equal(t, "Split the current branch into a stack of branches", cmd.Short)
+
+	byFileFlag := cmd.Flags().Lookup("by-file")
+	assert.NotNil(t, byFileFlag)
+	assert.Equal(t, "false", byFileFlag.DefValue)
+}
+
+func expectSplitPreamble(mockGitHelper *mocks.MockGitHelper) {
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature").Return(false)
+}
+
+func TestSplitCommand_RunE_UntrackedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectSplitPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().GetParent("feature").Return("", errors.New("no parent recorded for branch feature"))
+
+	cmd := branch.NewSplitCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no parent")
+}
+
+func TestSplitCommand_RunE_NeedsRestack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectSplitPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().GetParent("feature").Return("main", nil)
+	mockGitHelper.EXPECT().NeedsRestack("feature", "main").Return(true)
+
+	cmd := branch.NewSplitCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "restack it before splitting")
+}
+
+func TestSplitCommand_RunE_SingleCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectSplitPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().GetParent("feature").Return("main", nil)
+	mockGitHelper.EXPECT().NeedsRestack("feature", "main").Return(false)
+	mockGitHelper.EXPECT().GetCommits("main", "feature").Return([]string{"abc123 Add feature"}, nil)
+
+	cmd := branch.NewSplitCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least two commits")
+}
+
+func TestSplitCommand_RunE_ByFileSingleDirectory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectSplitPreamble(mockGitHelper)
+	mockGitHelper.EXPECT().GetParent("feature").Return("main", nil)
+	mockGitHelper.EXPECT().NeedsRestack("feature", "main").Return(false)
+	mockRunner.EXPECT().GitOutput("status", "--porcelain").Return("", nil)
+	mockGitHelper.EXPECT().GetRevision("main").Return("base", nil)
+	mockGitHelper.EXPECT().GetRevision("feature").Return("tip", nil)
+	mockRunner.EXPECT().
+		GitOutput("diff", "--name-status", "--no-renames", "base", "tip").
+		Return("M\tapi/server.go\nA\tapi/routes.go", nil)
+
+	cmd := branch.NewSplitCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("by-file", "true"))
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing to split by file")
+}
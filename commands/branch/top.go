@@ -0,0 +1,64 @@
+package branch
+
+import (
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type topCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewTopCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) topCommand {
+	return topCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc topCommand) Command() *cobra.Command {
+	var interactive bool
+
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "move to the top of the stack",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			currentBranch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			target, moved, err := climb(svc.gitHelper, currentBranch, -1, interactive)
+			if err != nil {
+				return err
+			}
+
+			if moved == 0 {
+				log.Infof("Already at the top of the stack on '%s'", currentBranch)
+				return nil
+			}
+
+			if err := svc.runner.Git("checkout", target); err != nil {
+				return log.Error("failed to checkout branch", err)
+			}
+
+			log.Successf("Moved up to branch '%s'", target)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false,
+		"Choose the child at every fork instead of reusing the last one")
+
+	return cmd
+}
@@ -0,0 +1,85 @@
+package branch_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := branch.NewTopCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "top", cmd.Use)
+	assert.Equal(t, "move to the top of the stack", cmd.Short)
+	assert.NotNil(t, cmd.Flags().Lookup("interactive"))
+}
+
+func TestTopCommand_RunE_WalksToLeaf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c", "feature-d"})
+	mockGitHelper.EXPECT().GetLastChild("feature-b").Return("feature-d", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-d").Return(nil)
+	mockRunner.EXPECT().Git("checkout", "feature-d").Return(nil)
+
+	cmd := branch.NewTopCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestTopCommand_RunE_IgnoresSelfParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-a", "feature-b"})
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-b"})
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+
+	cmd := branch.NewTopCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestTopCommand_RunE_ParentCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-a"})
+
+	cmd := branch.NewTopCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parent cycle")
+}
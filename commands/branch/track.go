@@ -1,6 +1,13 @@
 package branch
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/pavlovic265/265-gt/client"
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/ui/components"
@@ -11,20 +18,28 @@ import (
 type trackCommand struct {
 	runner    runner.Runner
 	gitHelper helpers.GitHelper
+	cliClient client.CliClient
 }
 
 func NewTrackCommand(
 	runner runner.Runner,
 	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
 ) trackCommand {
 	return trackCommand{
 		runner:    runner,
 		gitHelper: gitHelper,
+		cliClient: cliClient,
 	}
 }
 
 func (svc trackCommand) Command() *cobra.Command {
-	return &cobra.Command{
+	var (
+		fromPRs bool
+		auto    bool
+	)
+
+	cmd := &cobra.Command{
 		Use:     "track",
 		Aliases: []string{"tr"},
 		Short:   "track existing branch",
@@ -33,6 +48,10 @@ func (svc trackCommand) Command() *cobra.Command {
 				return err
 			}
 
+			if fromPRs {
+				return svc.trackFromPullRequests(cmd.Context())
+			}
+
 			branch, err := svc.gitHelper.GetCurrentBranch()
 			if err != nil {
 				return log.Error("failed to get current branch name", err)
@@ -43,27 +62,176 @@ func (svc trackCommand) Command() *cobra.Command {
 				return log.Error("failed to get branches", err)
 			}
 
-			var branchesWithoutCurrent []string
-			for _, b := range branches {
-				if b != branch {
-					branchesWithoutCurrent = append(branchesWithoutCurrent, b)
+			candidates := svc.rankParents(branch, branches)
+			inferred := svc.inferParent(cmd.Context(), candidates)
+
+			var selected string
+			if auto {
+				if inferred == "" {
+					return log.ErrorMsg(fmt.Sprintf("could not infer a parent for %s; run `gt track` to pick one", branch))
+				}
+				selected = inferred
+			} else {
+				choices := make([]string, 0, len(candidates))
+				if inferred != "" {
+					choices = append(choices, inferred)
+				}
+				for _, candidate := range candidates {
+					if candidate.branch != inferred {
+						choices = append(choices, candidate.branch)
+					}
 				}
-			}
 
-			selected, err := components.SelectString(branchesWithoutCurrent)
-			if err != nil {
-				return log.Error("failed to display branch selection", err)
-			}
-			if selected == "" {
-				return log.ErrorMsg("no branch selected")
+				selected, err = components.SelectString(choices)
+				if err != nil {
+					return log.Error("failed to display branch selection", err)
+				}
+				if selected == "" {
+					return log.ErrorMsg("no branch selected")
+				}
 			}
 
 			if err := svc.gitHelper.SetParent(selected, branch); err != nil {
 				return log.Error("failed to set parent", err)
 			}
 
-			log.Successf("Successfully tracking %s", branch)
+			if forkPoint, err := svc.gitHelper.GetMergeBase(selected, branch); err == nil {
+				if err := svc.gitHelper.SetParentRevision(branch, forkPoint); err != nil {
+					return log.Error("failed to record parent revision", err)
+				}
+			}
+
+			log.Successf("Successfully tracking %s on %s", branch, selected)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&fromPRs, "from-prs", false,
+		"Set the parent of every local branch with an open pull request to the pull request's base")
+	cmd.Flags().BoolVar(&auto, "auto", false, "Track the inferred parent without prompting")
+
+	return cmd
+}
+
+// parentCandidate is a branch the current one could be stacked on. ahead
+// counts the current branch's commits since their merge-base, behind the
+// candidate's; the closest parent has the fewest of both.
+type parentCandidate struct {
+	branch string
+	ahead  int
+	behind int
+}
+
+// rankParents orders the local branches by merge-base distance from branch,
+// leaving out branch and its descendants, which would form a cycle.
+func (svc trackCommand) rankParents(branch string, branches []string) []parentCandidate {
+	excluded := map[string]bool{branch: true}
+	queue := []string{branch}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range svc.gitHelper.GetChildren(current) {
+			if !excluded[child] {
+				excluded[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	var candidates []parentCandidate
+	for _, candidate := range branches {
+		if excluded[candidate] {
+			continue
+		}
+		ahead, err := svc.gitHelper.CountCommits(candidate, branch)
+		if err != nil {
+			ahead = math.MaxInt
+		}
+		behind, err := svc.gitHelper.CountCommits(branch, candidate)
+		if err != nil {
+			behind = math.MaxInt
+		}
+		candidates = append(candidates, parentCandidate{branch: candidate, ahead: ahead, behind: behind})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].ahead != candidates[j].ahead {
+			return candidates[i].ahead < candidates[j].ahead
+		}
+		return candidates[i].behind < candidates[j].behind
+	})
+	return candidates
+}
+
+// inferParent picks the closest ancestor that is already part of a stack,
+// either a tracked branch or the trunk. A candidate with commits the branch
+// lacks (behind > 0) has diverged from it and is left to the prompt.
+func (svc trackCommand) inferParent(ctx context.Context, candidates []parentCandidate) string {
+	parents := svc.gitHelper.GetParents()
+	trunk, _ := svc.gitHelper.GetTrunk(ctx)
+
+	for _, candidate := range candidates {
+		if candidate.ahead == math.MaxInt || candidate.behind != 0 {
+			continue
+		}
+		if _, tracked := parents[candidate.branch]; tracked || candidate.branch == trunk {
+			return candidate.branch
+		}
+	}
+	return ""
+}
+
+// trackFromPullRequests links each local branch to the base of its open pull
+// request. Branches already tracked on another parent are reported, not changed.
+func (svc trackCommand) trackFromPullRequests(ctx context.Context) error {
+	prs, err := svc.cliClient.ListPullRequests(ctx, []string{})
+	if err != nil {
+		return log.Error("failed to list pull requests", err)
+	}
+
+	branches, err := svc.gitHelper.GetBranches()
+	if err != nil {
+		return log.Error("failed to get branches", err)
+	}
+
+	tracked, conflicts := 0, 0
+	for _, pr := range prs {
+		if pr.BaseBranch == "" || !slices.Contains(branches, pr.Branch) {
+			continue
+		}
+
+		if !slices.Contains(branches, pr.BaseBranch) {
+			log.Warningf("Skipping %s: base %s is not a local branch", pr.Branch, pr.BaseBranch)
+			continue
+		}
+
+		parent, err := svc.gitHelper.GetParent(pr.Branch)
+		if err == nil && parent == pr.BaseBranch {
+			continue
+		}
+		if err == nil && parent != "" {
+			log.Warningf("Conflict on %s: tracked on %s, but PR #%d targets %s",
+				pr.Branch, parent, pr.Number, pr.BaseBranch)
+			conflicts++
+			continue
+		}
+
+		if err := svc.gitHelper.SetParent(pr.BaseBranch, pr.Branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to set parent of %s", pr.Branch), err)
+		}
+		if forkPoint, err := svc.gitHelper.GetMergeBase(pr.BaseBranch, pr.Branch); err == nil {
+			if err := svc.gitHelper.SetParentRevision(pr.Branch, forkPoint); err != nil {
+				return log.Error("failed to record parent revision", err)
+			}
+		}
+
+		log.Infof("%s → %s", pr.BaseBranch, pr.Branch)
+		tracked++
+	}
+
+	log.Successf("Tracked %d branches from pull requests", tracked)
+	if conflicts > 0 {
+		log.Warningf("%d branches kept their existing parent; use `gt move` to change them", conflicts)
+	}
+	return nil
 }
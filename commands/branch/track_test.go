@@ -1,11 +1,14 @@
 package branch_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/client"
 	"github.com/pavlovic265/265-gt/commands/branch"
 	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,8 +18,9 @@ func TestTrackCommand_Command(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
 
-	trackCmd := branch.NewTrackCommand(mockRunner, mockGitHelper)
+	trackCmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient)
 	cmd := trackCmd.Command()
 
 	assert.Equal(t, "track", cmd.Use)
@@ -30,10 +34,149 @@ func TestNewTrackCommand(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
 
-	trackCmd := branch.NewTrackCommand(mockRunner, mockGitHelper)
+	trackCmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient)
 	cmd := trackCmd.Command()
 
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "track", cmd.Use)
 }
+
+func TestTrackCommand_FromPRs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockCliClient.EXPECT().ListPullRequests(gomock.Any(), []string{}).Return([]client.PullRequest{
+		{Number: 1, Branch: "feature-a", BaseBranch: "main"},
+		{Number: 2, Branch: "feature-b", BaseBranch: "feature-a"},
+		{Number: 3, Branch: "feature-c", BaseBranch: "main"},
+		{Number: 4, Branch: "remote-only", BaseBranch: "main"},
+		{Number: 5, Branch: "feature-d", BaseBranch: "gone"},
+	}, nil)
+	mockGitHelper.EXPECT().GetBranches().
+		Return([]string{"main", "feature-a", "feature-b", "feature-c", "feature-d"}, nil)
+
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("", errors.New("no parent recorded"))
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetMergeBase("feature-a", "feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-b", "abc123").Return(nil)
+
+	// feature-c is already stacked elsewhere; the conflict is reported, not overwritten.
+	mockGitHelper.EXPECT().GetParent("feature-c").Return("feature-a", nil)
+
+	cmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("from-prs", "true"))
+
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestTrackCommand_AutoPicksClosestTrackedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetBranches().
+		Return([]string{"main", "feature-a", "scratch", "feature-b", "feature-c"}, nil)
+
+	// feature-c is stacked on feature-b and cannot become its parent.
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockGitHelper.EXPECT().GetChildren("feature-c").Return(nil)
+
+	mockGitHelper.EXPECT().CountCommits("main", "feature-b").Return(3, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-b", "main").Return(0, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-a", "feature-b").Return(1, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-b", "feature-a").Return(0, nil)
+	mockGitHelper.EXPECT().CountCommits("scratch", "feature-b").Return(0, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-b", "scratch").Return(2, nil)
+
+	// scratch is closest but untracked, so the inference falls to feature-a.
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{"feature-a": "main", "feature-c": "feature-b"})
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetMergeBase("feature-a", "feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-b", "abc123").Return(nil)
+
+	cmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("auto", "true"))
+
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestTrackCommand_AutoSkipsDivergedSibling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"main", "feature-a", "feature-b"}, nil)
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return(nil)
+
+	// feature-a forked from main next to feature-b, so it shares fewer commits
+	// but is not an ancestor.
+	mockGitHelper.EXPECT().CountCommits("main", "feature-b").Return(3, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-b", "main").Return(0, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-a", "feature-b").Return(1, nil)
+	mockGitHelper.EXPECT().CountCommits("feature-b", "feature-a").Return(2, nil)
+
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{"feature-a": "main"})
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+
+	mockGitHelper.EXPECT().SetParent("main", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetMergeBase("main", "feature-b").Return("abc123", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-b", "abc123").Return(nil)
+
+	cmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("auto", "true"))
+
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestTrackCommand_AutoWithoutCandidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("orphan", nil)
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"orphan", "scratch"}, nil)
+	mockGitHelper.EXPECT().GetChildren("orphan").Return(nil)
+	mockGitHelper.EXPECT().CountCommits("scratch", "orphan").Return(2, nil)
+	mockGitHelper.EXPECT().CountCommits("orphan", "scratch").Return(1, nil)
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{})
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("", errors.New("no trunk branch found"))
+
+	cmd := branch.NewTrackCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	assert.NoError(t, cmd.Flags().Set("auto", "true"))
+
+	err := cmd.RunE(cmd, []string{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not infer a parent")
+}
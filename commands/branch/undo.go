@@ -0,0 +1,70 @@
+package branch
+
+import (
+	"fmt"
+
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type undoCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewUndoCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) undoCommand {
+	return undoCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc undoCommand) Command() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo the last gt operation",
+		Long: "Restore the branch refs and parent links the last recorded gt operation changed, " +
+			"including deleted branches. Refuses if those branches changed since, unless --force is given.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			operations, err := svc.gitHelper.GetOperationLog()
+			if err != nil {
+				return log.Error("failed to read operation log", err)
+			}
+			if len(operations) == 0 {
+				return log.ErrorMsg("nothing to undo")
+			}
+
+			last := operations[0]
+			if err := svc.gitHelper.UndoOperation(last, force); err != nil {
+				return log.Error(fmt.Sprintf("failed to undo %s", last.Operation), err)
+			}
+			if err := svc.gitHelper.DeleteOperation(last.ID); err != nil {
+				return log.Error("failed to update operation log", err)
+			}
+			if err := svc.gitHelper.DiscardOperation(); err != nil {
+				return log.Error("failed to clear pending operation", err)
+			}
+
+			log.Successf("Undid %s from %s", last.Operation, last.CreatedAt.Format("2006-01-02 15:04:05"))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Undo even if the changed branches moved since")
+
+	return cmd
+}
@@ -0,0 +1,125 @@
+package branch_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/branch"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/pavlovic265/265-gt/oplog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndoCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	undoCmd := branch.NewUndoCommand(mockRunner, mockGitHelper)
+	cmd := undoCmd.Command()
+
+	assert.Equal(t, "undo", cmd.Use)
+	assert.Equal(t, "Undo the last gt operation", cmd.Short)
+}
+
+func TestUndoCommand_RunE_RestoresLastOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	last := &oplog.Snapshot{ID: "2-delete", Operation: "delete", CreatedAt: time.Now()}
+	older := &oplog.Snapshot{ID: "1-restack", Operation: "restack", CreatedAt: time.Now()}
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetOperationLog().Return([]*oplog.Snapshot{last, older}, nil)
+	mockGitHelper.EXPECT().UndoOperation(last, false).Return(nil)
+	mockGitHelper.EXPECT().DeleteOperation("2-delete").Return(nil)
+	mockGitHelper.EXPECT().DiscardOperation().Return(nil)
+
+	cmd := branch.NewUndoCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestUndoCommand_RunE_BranchesMovedKeepsLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	last := &oplog.Snapshot{ID: "1-restack", Operation: "restack", CreatedAt: time.Now()}
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetOperationLog().Return([]*oplog.Snapshot{last}, nil)
+	mockGitHelper.EXPECT().UndoOperation(last, false).Return(errors.New("a changed since the restack"))
+
+	cmd := branch.NewUndoCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Error(t, cmd.RunE(cmd, nil))
+}
+
+func TestUndoCommand_RunE_Force(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	last := &oplog.Snapshot{ID: "1-restack", Operation: "restack", CreatedAt: time.Now()}
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetOperationLog().Return([]*oplog.Snapshot{last}, nil)
+	mockGitHelper.EXPECT().UndoOperation(last, true).Return(nil)
+	mockGitHelper.EXPECT().DeleteOperation("1-restack").Return(nil)
+	mockGitHelper.EXPECT().DiscardOperation().Return(nil)
+
+	cmd := branch.NewUndoCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("force", "true"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestUndoCommand_RunE_NothingToUndo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetOperationLog().Return(nil, nil)
+
+	cmd := branch.NewUndoCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Error(t, cmd.RunE(cmd, nil))
+}
+
+func TestUndoCommand_RunE_RebaseInProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(true)
+
+	cmd := branch.NewUndoCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Error(t, cmd.RunE(cmd, nil))
+}
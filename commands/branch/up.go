@@ -1,6 +1,10 @@
 package branch
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/ui/components"
@@ -24,37 +28,49 @@ func NewUpCommand(
 }
 
 func (svc upCommand) Command() *cobra.Command {
-	return &cobra.Command{
+	var interactive bool
+
+	cmd := &cobra.Command{
 		Use:   "up",
 		Short: "move to branch up in stack",
+		Long: "Move up to a child branch, or N levels with `gt up N`. At a fork the child picked " +
+			"last time is reused; pass --interactive to choose again.",
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
 				return err
 			}
 
+			steps, err := parseSteps(args)
+			if err != nil {
+				return err
+			}
+
 			currentBranch, err := svc.gitHelper.GetCurrentBranch()
 			if err != nil {
 				return log.Error("failed to get current branch name", err)
 			}
 
-			children := svc.gitHelper.GetChildren(currentBranch)
+			target, moved, err := climb(svc.gitHelper, currentBranch, steps, interactive)
+			if err != nil {
+				return err
+			}
 
-			if len(children) == 0 {
+			if moved == 0 {
 				return log.ErrorMsg("cannot move up: no child branches available")
 			}
-
-			if len(children) == 1 {
-				err := svc.checkoutBranch(children[0])
-				if err != nil {
-					return err
-				}
-			} else {
-				return svc.selectAndCheckoutBranch(children)
+			if moved < steps {
+				log.Infof("Reached the top of the stack after %d of %d steps", moved, steps)
 			}
 
-			return nil
+			return svc.checkoutBranch(target)
 		},
 	}
+
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false,
+		"Choose the child at every fork instead of reusing the last one")
+
+	return cmd
 }
 
 func (svc upCommand) checkoutBranch(
@@ -67,14 +83,70 @@ func (svc upCommand) checkoutBranch(
 	return nil
 }
 
-func (svc upCommand) selectAndCheckoutBranch(choices []string) error {
-	selected, err := components.SelectString(choices)
+// parseSteps reads the optional step count of `gt up N` / `gt down N`.
+func parseSteps(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		return 0, log.ErrorMsg(fmt.Sprintf("invalid step count %q: must be a positive number", args[0]))
+	}
+	return steps, nil
+}
+
+// climb follows children from branch for up to steps levels, or to a leaf when
+// steps is negative. It returns the branch reached and how many levels it moved,
+// and fails instead of looping when the recorded parents form a cycle.
+func climb(gitHelper helpers.GitHelper, branch string, steps int, interactive bool) (string, int, error) {
+	visited := map[string]bool{branch: true}
+	moved := 0
+	for steps < 0 || moved < steps {
+		next, err := nextChild(gitHelper, branch, interactive)
+		if err != nil {
+			return "", 0, err
+		}
+		if next == "" {
+			break
+		}
+		if visited[next] {
+			return "", 0, log.ErrorMsg(fmt.Sprintf(
+				"parent cycle detected at '%s'; run `gt fsck --fix` to repair it", next))
+		}
+		visited[next] = true
+		branch = next
+		moved++
+	}
+	return branch, moved, nil
+}
+
+// nextChild picks the child to move up to. At a fork it reuses the remembered
+// child unless interactive is set, and otherwise prompts and remembers the pick.
+func nextChild(gitHelper helpers.GitHelper, branch string, interactive bool) (string, error) {
+	children := slices.DeleteFunc(gitHelper.GetChildren(branch), func(child string) bool {
+		return child == branch
+	})
+	switch len(children) {
+	case 0:
+		return "", nil
+	case 1:
+		return children[0], nil
+	}
+
+	if !interactive {
+		if last, err := gitHelper.GetLastChild(branch); err == nil && slices.Contains(children, last) {
+			return last, nil
+		}
+	}
+
+	selected, err := components.SelectString(children)
 	if err != nil {
-		return log.Error("failed to display branch selection menu", err)
+		return "", log.Error("failed to display branch selection menu", err)
 	}
 	if selected == "" {
-		return log.ErrorMsg("no branch selected for checkout")
+		return "", log.ErrorMsg("no branch selected for checkout")
 	}
 
-	return svc.checkoutBranch(selected)
+	_ = gitHelper.SetLastChild(branch, selected)
+	return selected, nil
 }
@@ -36,3 +36,96 @@ func TestNewUpCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "up", cmd.Use)
 }
+
+func TestUpCommand_RunE_FollowsRememberedChildAtFork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+	mockGitHelper.EXPECT().GetChildren("main").Return([]string{"feature-a", "feature-b"})
+	mockGitHelper.EXPECT().GetLastChild("main").Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return([]string{"feature-c"})
+	mockRunner.EXPECT().Git("checkout", "feature-c").Return(nil)
+
+	cmd := branch.NewUpCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, []string{"2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestUpCommand_RunE_StopsAtTop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().GetChildren("feature-b").Return(nil)
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+
+	cmd := branch.NewUpCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, []string{"5"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestUpCommand_RunE_NoChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return(nil)
+
+	cmd := branch.NewUpCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no child branches available")
+}
+
+func TestUpCommand_RunE_SelfParentIsNotAChild(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-a"})
+
+	cmd := branch.NewUpCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, []string{"3"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no child branches available")
+}
+
+func TestUpCommand_RunE_InvalidSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+
+	cmd := branch.NewUpCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, []string{"0"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid step count")
+}
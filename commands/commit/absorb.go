@@ -0,0 +1,471 @@
+package commit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/pavlovic265/265-gt/utils/patch"
+	"github.com/spf13/cobra"
+)
+
+type absorbCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+// stackCommit is a commit of the current stack that hunks can be absorbed into.
+type stackCommit struct {
+	hash    string
+	subject string
+	branch  string
+	// age orders commits within the stack; higher is newer.
+	age int
+}
+
+// hunkTarget records where one staged hunk goes, or why it stays staged.
+type hunkTarget struct {
+	file   int
+	hunk   int
+	commit *stackCommit
+	reason string
+}
+
+func NewAbsorbCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) absorbCommand {
+	return absorbCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc absorbCommand) Command() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "absorb",
+		Aliases: []string{"ab"},
+		Short:   "Absorb staged hunks into the stack commits that last touched them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			branch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			stack, base, err := svc.currentStack(cmd.Context(), branch)
+			if err != nil {
+				return err
+			}
+
+			commits, err := svc.stackCommits(stack, base)
+			if err != nil {
+				return err
+			}
+
+			files, err := svc.stagedFiles()
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return log.ErrorMsg("no staged changes to absorb")
+			}
+
+			targets := svc.findTargets(files, commits)
+			svc.printPlan(files, targets)
+
+			if dryRun {
+				return nil
+			}
+
+			absorbed := 0
+			for _, target := range targets {
+				if target.commit != nil {
+					absorbed++
+				}
+			}
+			if absorbed == 0 {
+				return log.ErrorMsg("none of the staged hunks can be absorbed")
+			}
+
+			return svc.absorb(branch, stack, base, files, targets)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show where each staged hunk would go without changing anything")
+
+	return cmd
+}
+
+// currentStack returns the branches from the bottom of the current stack up to
+// branch, and the commit the stack starts from.
+func (svc absorbCommand) currentStack(ctx context.Context, branch string) ([]string, string, error) {
+	var stack []string
+	bottom := ""
+	seen := map[string]bool{}
+	for current := branch; !seen[current]; {
+		seen[current] = true
+		parent, err := svc.gitHelper.GetParent(current)
+		if err != nil || parent == "" {
+			break
+		}
+		stack = append([]string{current}, stack...)
+		bottom = parent
+		if svc.gitHelper.IsProtectedBranch(ctx, parent) {
+			break
+		}
+		current = parent
+	}
+
+	if len(stack) == 0 {
+		return nil, "", log.ErrorMsg(fmt.Sprintf("branch %s has no parent; run `gt track` first", branch))
+	}
+
+	for i := 1; i < len(stack); i++ {
+		if svc.gitHelper.NeedsRestack(stack[i], stack[i-1]) {
+			return nil, "", log.ErrorMsg(fmt.Sprintf("branch %s is behind %s; restack the stack before absorbing",
+				stack[i], stack[i-1]))
+		}
+	}
+
+	base, err := svc.gitHelper.GetMergeBase(bottom, stack[0])
+	if err != nil {
+		return nil, "", log.Error(fmt.Sprintf("failed to find where %s starts", stack[0]), err)
+	}
+	return stack, base, nil
+}
+
+func (svc absorbCommand) stackCommits(stack []string, base string) (map[string]*stackCommit, error) {
+	commits := make(map[string]*stackCommit)
+	age := 0
+	lower := base
+	for _, branch := range stack {
+		output, err := svc.runner.GitOutput("log", "--reverse", "--format=%H %s", lower+".."+branch)
+		if err != nil {
+			return nil, log.Error(fmt.Sprintf("failed to list commits of %s", branch), err)
+		}
+		for _, line := range strings.Split(output, "\n") {
+			hash, subject, _ := strings.Cut(strings.TrimSpace(line), " ")
+			if hash == "" {
+				continue
+			}
+			age++
+			commits[hash] = &stackCommit{hash: hash, subject: subject, branch: branch, age: age}
+		}
+		lower = branch
+	}
+	return commits, nil
+}
+
+// stagedFiles reads the staged diff through a file: captured output is trimmed,
+// which would corrupt whitespace at the end of the last hunk.
+func (svc absorbCommand) stagedFiles() ([]patch.File, error) {
+	tmp, err := os.CreateTemp("", "gt-absorb-*.diff")
+	if err != nil {
+		return nil, log.Error("failed to create temporary file", err)
+	}
+	_ = tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := svc.runner.Git("diff", "--cached", "-U0", "--no-color", "--no-ext-diff", "--no-renames",
+		"--output="+tmp.Name()); err != nil {
+		return nil, log.Error("failed to read staged changes", err)
+	}
+
+	diff, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, log.Error("failed to read staged changes", err)
+	}
+
+	files, err := patch.Parse(string(diff))
+	if err != nil {
+		return nil, log.Error("failed to parse staged changes", err)
+	}
+	return files, nil
+}
+
+// findTargets blames the lines each hunk replaces. A hunk is absorbed when all
+// of them come from one branch of the stack, into the newest such commit.
+func (svc absorbCommand) findTargets(files []patch.File, commits map[string]*stackCommit) []hunkTarget {
+	var targets []hunkTarget
+	for f, file := range files {
+		for h, hunk := range file.Hunks {
+			target := hunkTarget{file: f, hunk: h}
+			switch {
+			case !file.Modifiable:
+				target.reason = "not a modification of an existing file"
+			case hunk.OldCount == 0:
+				target.reason = "only adds lines"
+			default:
+				target.commit, target.reason = svc.blameHunk(file.Path, hunk, commits)
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func (svc absorbCommand) blameHunk(
+	path string, hunk patch.Hunk, commits map[string]*stackCommit,
+) (*stackCommit, string) {
+	output, err := svc.runner.GitOutput("blame", "-l", "-s",
+		"-L", fmt.Sprintf("%d,+%d", hunk.OldStart, hunk.OldCount), "HEAD", "--", path)
+	if err != nil {
+		return nil, "could not blame lines"
+	}
+
+	var newest *stackCommit
+	for _, line := range strings.Split(output, "\n") {
+		hash, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		commit, ok := commits[hash]
+		if !ok {
+			return nil, "touches lines from outside the stack"
+		}
+		if newest != nil && newest.branch != commit.branch {
+			return nil, "touches lines from several branches"
+		}
+		if newest == nil || commit.age > newest.age {
+			newest = commit
+		}
+	}
+	if newest == nil {
+		return nil, "could not blame lines"
+	}
+	return newest, ""
+}
+
+func (svc absorbCommand) printPlan(files []patch.File, targets []hunkTarget) {
+	for _, target := range targets {
+		file := files[target.file]
+		hunk := file.Hunks[target.hunk]
+		location := fmt.Sprintf("%s:%d", file.Path, hunk.OldStart)
+		if file.Path == "" {
+			location = file.Header[0]
+		}
+
+		if target.commit != nil {
+			log.Infof("%s → %s (%s %s)", location, target.commit.branch, shortHash(target.commit.hash),
+				target.commit.subject)
+		} else {
+			log.Infof("%s stays staged: %s", location, target.reason)
+		}
+	}
+}
+
+func (svc absorbCommand) absorb(
+	branch string, stack []string, base string, files []patch.File, targets []hunkTarget,
+) error {
+	if err := svc.gitHelper.BeginOperation("absorb"); err != nil {
+		return log.Error("failed to record operation", err)
+	}
+	defer svc.gitHelper.SettleOperation()
+
+	offStack, err := svc.pinOffStackChildren(stack)
+	if err != nil {
+		return err
+	}
+
+	// Rebuild the index one fixup at a time: start from HEAD and apply only the
+	// hunks that belong to each target commit.
+	if err := svc.runner.Git("reset", "-q"); err != nil {
+		return log.Error("failed to unstage changes", err)
+	}
+
+	applied := make(map[int][]patch.Hunk)
+	var order []*stackCommit
+	byCommit := make(map[*stackCommit][]hunkTarget)
+	for _, target := range targets {
+		if target.commit == nil {
+			continue
+		}
+		if _, ok := byCommit[target.commit]; !ok {
+			order = append(order, target.commit)
+		}
+		byCommit[target.commit] = append(byCommit[target.commit], target)
+	}
+
+	branches := map[string]bool{}
+	for _, commit := range order {
+		if err := svc.applyCached(files, byCommit[commit], applied); err != nil {
+			return err
+		}
+		if err := svc.runner.Git("commit", "-q", "--no-verify", "--fixup="+commit.hash); err != nil {
+			return log.Error(fmt.Sprintf("failed to create fixup for %s", shortHash(commit.hash)), err)
+		}
+		branches[commit.branch] = true
+	}
+
+	var remaining []hunkTarget
+	for _, target := range targets {
+		if target.commit == nil {
+			remaining = append(remaining, target)
+		}
+	}
+
+	// Hunks that were not absorbed and unstaged edits are stashed until every
+	// branch has been rewritten, since rebases need a clean working tree.
+	stashed, err := svc.stash()
+	if err != nil {
+		return err
+	}
+
+	if err := svc.gitHelper.SetPendingQueue(offStack); err != nil {
+		return log.Error("failed to save pending restack queue", err)
+	}
+	if err := svc.runner.Git("-c", "sequence.editor=:", "rebase", "-q", "-i", "--autosquash",
+		"--update-refs", base); err != nil {
+		_ = svc.gitHelper.PauseOperation()
+		log.Warning("Rebase paused due to conflicts. Resolve them, then run `gt cont` or `gt abort`.")
+		svc.warnStashed(stashed)
+		return log.Error("failed to squash fixups", err)
+	}
+
+	for i := 1; i < len(stack); i++ {
+		if revision, err := svc.gitHelper.GetRevision(stack[i-1]); err == nil {
+			if err := svc.gitHelper.SetParentRevision(stack[i], revision); err != nil {
+				return log.Error("failed to record parent revision", err)
+			}
+		}
+	}
+
+	if len(offStack) > 0 {
+		if _, err := svc.gitHelper.RestackBranches(offStack); err != nil {
+			svc.warnStashed(stashed)
+			return log.Error("failed to restack descendants; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", "-q", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout branch %s", branch), err)
+		}
+	}
+
+	if stashed {
+		if err := svc.runner.Git("stash", "pop", "-q"); err != nil {
+			return log.Error("failed to restore uncommitted changes; they are kept in `git stash`", err)
+		}
+	}
+
+	if len(remaining) > 0 {
+		if err := svc.applyCached(files, remaining, applied); err != nil {
+			log.Warning("Could not re-stage the hunks that were not absorbed; they are still in the working tree")
+		}
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+
+	names := make([]string, 0, len(branches))
+	for _, name := range stack {
+		if branches[name] {
+			names = append(names, name)
+		}
+	}
+	log.Successf("Absorbed %d hunks into %s", len(targets)-len(remaining), strings.Join(names, ", "))
+	return nil
+}
+
+func (svc absorbCommand) stash() (bool, error) {
+	status, err := svc.runner.GitOutput("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, log.Error("failed to check working tree", err)
+	}
+	if status == "" {
+		return false, nil
+	}
+	if err := svc.runner.Git("stash", "push", "-q", "-m", "gt absorb"); err != nil {
+		return false, log.Error("failed to stash uncommitted changes", err)
+	}
+	return true, nil
+}
+
+func (svc absorbCommand) warnStashed(stashed bool) {
+	if stashed {
+		log.Warning("Uncommitted changes were stashed; run `git stash pop` once the restack is finished.")
+	}
+}
+
+// pinOffStackChildren returns branches stacked on the stack but not part of
+// it. They are rebased afterwards, so their parent revision is recorded now
+// while it still points at the commits they were built on.
+func (svc absorbCommand) pinOffStackChildren(stack []string) ([]string, error) {
+	inStack := make(map[string]bool, len(stack))
+	for _, branch := range stack {
+		inStack[branch] = true
+	}
+
+	var offStack []string
+	for _, branch := range stack {
+		tip, err := svc.gitHelper.GetRevision(branch)
+		if err != nil {
+			return nil, log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
+		}
+		// Children in the stack are rebased along with it, so pinning them
+		// too is harmless.
+		if err := svc.gitHelper.PinChildren(branch, tip); err != nil {
+			return nil, log.Error("failed to record parent revision", err)
+		}
+		for _, child := range svc.gitHelper.GetChildren(branch) {
+			if !inStack[child] {
+				offStack = append(offStack, child)
+			}
+		}
+	}
+	return offStack, nil
+}
+
+// applyCached stages the given hunks on top of those already applied.
+func (svc absorbCommand) applyCached(files []patch.File, targets []hunkTarget, applied map[int][]patch.Hunk) error {
+	selected := make(map[int][]patch.Hunk)
+	var fileOrder []int
+	for _, target := range targets {
+		if _, ok := selected[target.file]; !ok {
+			fileOrder = append(fileOrder, target.file)
+		}
+		selected[target.file] = append(selected[target.file], files[target.file].Hunks[target.hunk])
+	}
+
+	var b strings.Builder
+	for _, f := range fileOrder {
+		b.WriteString(patch.Render(files[f], selected[f], applied[f]))
+	}
+
+	tmp, err := os.CreateTemp("", "gt-absorb-*.patch")
+	if err != nil {
+		return log.Error("failed to create temporary file", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		_ = tmp.Close()
+		return log.Error("failed to write patch", err)
+	}
+	_ = tmp.Close()
+
+	if err := svc.runner.Git("apply", "--cached", "--unidiff-zero", tmp.Name()); err != nil {
+		return log.Error("failed to stage hunks", err)
+	}
+
+	for _, f := range fileOrder {
+		applied[f] = append(applied[f], selected[f]...)
+	}
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,160 @@
+package commit_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/commit"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	hashA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	hashB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+const stagedDiff = `diff --git a/f b/f
+index 1111111..2222222 100644
+--- a/f
++++ b/f
+@@ -2 +2 @@
+-A2 helo
++A2 hello
+@@ -5 +5 @@
+-B5 wrold
++B5 world
+@@ -6,0 +7 @@
++tail
+`
+
+func expectDiff(mockRunner *mocks.MockRunner, diff string) {
+	mockRunner.EXPECT().
+		Git("diff", "--cached", "-U0", "--no-color", "--no-ext-diff", "--no-renames", gomock.Any()).
+		DoAndReturn(func(args ...string) error {
+			path := strings.TrimPrefix(args[len(args)-1], "--output=")
+			return os.WriteFile(path, []byte(diff), 0o600)
+		})
+}
+
+func expectStack(mockRunner *mocks.MockRunner, mockGitHelper *mocks.MockGitHelper) {
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "main").Return(true)
+	mockGitHelper.EXPECT().NeedsRestack("feature-b", "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetMergeBase("main", "feature-a").Return("base", nil)
+	mockRunner.EXPECT().
+		GitOutput("log", "--reverse", "--format=%H %s", "base..feature-a").
+		Return(hashA+" a change", nil)
+	mockRunner.EXPECT().
+		GitOutput("log", "--reverse", "--format=%H %s", "feature-a..feature-b").
+		Return(hashB+" b change", nil)
+}
+
+func TestAbsorbCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := commit.NewAbsorbCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "absorb", cmd.Use)
+	assert.Equal(t, []string{"ab"}, cmd.Aliases)
+	assert.Equal(t, "Absorb staged hunks into the stack commits that last touched them", cmd.Short)
+
+	dryRunFlag := cmd.Flags().Lookup("dry-run")
+	assert.NotNil(t, dryRunFlag)
+	assert.Equal(t, "n", dryRunFlag.Shorthand)
+	assert.Equal(t, "false", dryRunFlag.DefValue)
+}
+
+func TestAbsorbCommand_RunE_UntrackedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("scratch", nil)
+	mockGitHelper.EXPECT().GetParent("scratch").Return("", nil)
+
+	cmd := commit.NewAbsorbCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no parent")
+}
+
+func TestAbsorbCommand_RunE_NothingStaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectStack(mockRunner, mockGitHelper)
+	expectDiff(mockRunner, "")
+
+	cmd := commit.NewAbsorbCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no staged changes")
+}
+
+func TestAbsorbCommand_RunE_DryRunOnlyBlames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectStack(mockRunner, mockGitHelper)
+	expectDiff(mockRunner, stagedDiff)
+	mockRunner.EXPECT().
+		GitOutput("blame", "-l", "-s", "-L", "2,+1", "HEAD", "--", "f").
+		Return(hashA+" 2) A2 helo", nil)
+	mockRunner.EXPECT().
+		GitOutput("blame", "-l", "-s", "-L", "5,+1", "HEAD", "--", "f").
+		Return(hashB+" 5) B5 wrold", nil)
+
+	cmd := commit.NewAbsorbCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("dry-run", "true"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestAbsorbCommand_RunE_NothingAbsorbable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	expectStack(mockRunner, mockGitHelper)
+	expectDiff(mockRunner, stagedDiff)
+	mockRunner.EXPECT().
+		GitOutput("blame", "-l", "-s", "-L", "2,+1", "HEAD", "--", "f").
+		Return("^cccccccccccccccccccccccccccccccccccccc 2) A2 helo", nil)
+	mockRunner.EXPECT().
+		GitOutput("blame", "-l", "-s", "-L", "5,+1", "HEAD", "--", "f").
+		Return("dddddddddddddddddddddddddddddddddddddddd 5) B5 wrold", nil)
+
+	cmd := commit.NewAbsorbCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "none of the staged hunks can be absorbed")
+}
@@ -0,0 +1,155 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type modifyCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewModifyCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) modifyCommand {
+	return modifyCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc modifyCommand) Command() *cobra.Command {
+	var (
+		newCommit bool
+		all       bool
+		message   string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "modify",
+		Aliases: []string{"m"},
+		Short:   "Amend the current branch and restack its descendants",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			branch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			tip, err := svc.gitHelper.GetRevision(branch)
+			if err != nil {
+				return log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
+			}
+
+			if !newCommit && !svc.hasOwnCommits(branch) {
+				log.Infof("%s has no commits of its own yet; creating a new commit instead of amending", branch)
+				newCommit = true
+			}
+
+			if err := svc.gitHelper.BeginOperation("modify"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
+
+			if err := svc.commit(all, newCommit, message); err != nil {
+				return err
+			}
+
+			return svc.restackDescendants(branch, tip)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&newCommit, "commit", "c", false, "Create a new commit instead of amending")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Stage all changes before committing")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Commit message (amend keeps the current one by default)")
+
+	return cmd
+}
+
+func (svc modifyCommand) commit(all, newCommit bool, message string) error {
+	if all {
+		if err := svc.runner.Git("add", "-A"); err != nil {
+			return log.Error("failed to stage changes", err)
+		}
+	}
+
+	args := []string{"commit"}
+	if !newCommit {
+		args = append(args, "--amend")
+	}
+	switch {
+	case message != "":
+		args = append(args, "-m", message)
+	case !newCommit:
+		args = append(args, "--no-edit")
+	}
+
+	if err := svc.runner.Git(args...); err != nil {
+		return log.Error("failed to create commit", err)
+	}
+
+	if newCommit {
+		log.Success("Commit created")
+	} else {
+		log.Success("Commit amended")
+	}
+	return nil
+}
+
+// hasOwnCommits reports whether branch is ahead of its parent. Amending a
+// fresh branch would otherwise rewrite the parent's tip commit into it.
+func (svc modifyCommand) hasOwnCommits(branch string) bool {
+	parent, err := svc.gitHelper.GetParent(branch)
+	if err != nil || parent == "" {
+		return true
+	}
+	count, err := svc.gitHelper.CountCommits(parent, branch)
+	if err != nil {
+		return true
+	}
+	return count > 0
+}
+
+// restackDescendants replays every branch stacked on branch. Children are
+// pinned to the pre-commit tip first, so an amend only replays their own
+// commits.
+func (svc modifyCommand) restackDescendants(branch, oldTip string) error {
+	if err := svc.gitHelper.PinChildren(branch, oldTip); err != nil {
+		return log.Error("failed to record parent revision", err)
+	}
+
+	children := svc.gitHelper.GetChildren(branch)
+	descendants := svc.gitHelper.GetDescendants(children)
+
+	if len(children) > 0 {
+		if _, err := svc.gitHelper.RestackBranches(children); err != nil {
+			return log.Error("failed to restack descendants; resolve conflicts and run `gt cont`", err)
+		}
+		if err := svc.runner.Git("checkout", branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to checkout branch %s", branch), err)
+		}
+	}
+
+	if err := svc.gitHelper.EndOperation(); err != nil {
+		return log.Error("failed to finish operation", err)
+	}
+
+	if len(descendants) > 0 {
+		log.Successf("Restacked %d descendants: %s", len(descendants), strings.Join(descendants, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package commit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/commit"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModifyCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := commit.NewModifyCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "modify", cmd.Use)
+	assert.Equal(t, []string{"m"}, cmd.Aliases)
+	assert.Equal(t, "Amend the current branch and restack its descendants", cmd.Short)
+
+	for name, shorthand := range map[string]string{"commit": "c", "all": "a", "message": "m"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag)
+		assert.Equal(t, shorthand, flag.Shorthand)
+	}
+}
+
+func TestModifyCommand_RunE_AmendsAndRestacksDescendants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("old-tip", nil)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockGitHelper.EXPECT().CountCommits("main", "feature-a").Return(1, nil)
+	mockGitHelper.EXPECT().BeginOperation("modify").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("add", "-A").Return(nil)
+	mockRunner.EXPECT().Git("commit", "--amend", "--no-edit").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-a", "old-tip").Return(nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().GetDescendants([]string{"feature-b"}).Return([]string{"feature-b", "feature-c"})
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-b"}).Return(2, nil)
+	mockRunner.EXPECT().Git("checkout", "feature-a").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := commit.NewModifyCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("all", "true"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestModifyCommand_RunE_NewCommitWithoutDescendants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("old-tip", nil)
+	mockGitHelper.EXPECT().BeginOperation("modify").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("commit", "-m", "Fix typo").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-a", "old-tip").Return(nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return(nil)
+	mockGitHelper.EXPECT().GetDescendants(nil).Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := commit.NewModifyCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("commit", "true"))
+	assert.NoError(t, cmd.Flags().Set("message", "Fix typo"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestModifyCommand_RunE_CommitsInsteadOfAmendingFreshBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("main-tip", nil)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockGitHelper.EXPECT().CountCommits("main", "feature-a").Return(0, nil)
+	mockGitHelper.EXPECT().BeginOperation("modify").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("commit", "-m", "Add feature").Return(nil)
+	mockGitHelper.EXPECT().PinChildren("feature-a", "main-tip").Return(nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return(nil)
+	mockGitHelper.EXPECT().GetDescendants(nil).Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := commit.NewModifyCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("message", "Add feature"))
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestModifyCommand_RunE_CommitFailureLeavesBranchesAlone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("old-tip", nil)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockGitHelper.EXPECT().CountCommits("main", "feature-a").Return(2, nil)
+	mockGitHelper.EXPECT().BeginOperation("modify").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("commit", "--amend", "--no-edit").Return(errors.New("nothing to commit"))
+
+	cmd := commit.NewModifyCommand(mockRunner, mockGitHelper).Command()
+
+	err := cmd.RunE(cmd, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create commit")
+}
@@ -8,4 +8,6 @@ import (
 
 func RegisterCommands(root *cobra.Command, r runner.Runner, gh helpers.GitHelper) {
 	root.AddCommand(NewCommitCommand(r, gh).Command())
+	root.AddCommand(NewModifyCommand(r, gh).Command())
+	root.AddCommand(NewAbsorbCommand(r, gh).Command())
 }
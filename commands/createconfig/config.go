@@ -2,6 +2,7 @@ package createconfig
 
 import (
 	"github.com/pavlovic265/265-gt/config"
+	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/spf13/cobra"
 )
@@ -9,15 +10,18 @@ import (
 type configCommand struct {
 	runner        runner.Runner
 	configManager config.ConfigManager
+	gitHelper     helpers.GitHelper
 }
 
 func NewConfigCommand(
 	runner runner.Runner,
 	configManager config.ConfigManager,
+	gitHelper helpers.GitHelper,
 ) configCommand {
 	return configCommand{
 		runner:        runner,
 		configManager: configManager,
+		gitHelper:     gitHelper,
 	}
 }
 
@@ -28,7 +32,7 @@ func (svc configCommand) Command() *cobra.Command {
 		Short:   "create config",
 	}
 	configCmd.AddCommand(NewGlobalCommand(svc.runner, svc.configManager).Command())
-	configCmd.AddCommand(NewLocalCommand(svc.runner, svc.configManager).Command())
+	configCmd.AddCommand(NewLocalCommand(svc.runner, svc.configManager, svc.gitHelper).Command())
 
 	return configCmd
 }
@@ -15,8 +15,9 @@ func TestConfigCommand_Command(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockConfigManager := mocks.NewMockConfigManager(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
 
-	configCmd := createconfig.NewConfigCommand(mockRunner, mockConfigManager)
+	configCmd := createconfig.NewConfigCommand(mockRunner, mockConfigManager, mockGitHelper)
 	cmd := configCmd.Command()
 
 	assert.Equal(t, "config", cmd.Use)
@@ -30,8 +31,9 @@ func TestNewConfigCommand(t *testing.T) {
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	mockConfigManager := mocks.NewMockConfigManager(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
 
-	configCmd := createconfig.NewConfigCommand(mockRunner, mockConfigManager)
+	configCmd := createconfig.NewConfigCommand(mockRunner, mockConfigManager, mockGitHelper)
 	cmd := configCmd.Command()
 
 	assert.NotNil(t, cmd)
@@ -2,6 +2,7 @@ package createconfig
 
 import (
 	"github.com/pavlovic265/265-gt/config"
+	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/utils/log"
 	"github.com/spf13/cobra"
@@ -10,15 +11,18 @@ import (
 type localCommand struct {
 	runner        runner.Runner
 	configManager config.ConfigManager
+	gitHelper     helpers.GitHelper
 }
 
 func NewLocalCommand(
 	runner runner.Runner,
 	configManager config.ConfigManager,
+	gitHelper helpers.GitHelper,
 ) localCommand {
 	return localCommand{
 		runner:        runner,
 		configManager: configManager,
+		gitHelper:     gitHelper,
 	}
 }
 
@@ -29,6 +33,10 @@ func (svc localCommand) Command() *cobra.Command {
 		Short:              "generate local config",
 		DisableFlagParsing: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
 			cfg, ok := config.GetConfig(cmd.Context())
 			if !ok {
 				return log.ErrorMsg("config not loaded")
@@ -52,11 +60,35 @@ func (svc localCommand) Command() *cobra.Command {
 				cfg.Local.MergeMethod = mergeMethod
 			}
 
+			trunk, err := svc.selectTrunk(cfg.Local.Trunk)
+			if err != nil {
+				return err
+			}
+			if trunk != "" {
+				cfg.Local.Trunk = trunk
+			}
+
 			cfg.MarkLocalDirty()
 
-			log.Info("Note: 'main' and 'master' are protected by default")
+			log.Info("Note: the trunk, 'main' and 'master' are protected by default")
 			log.Success("Local configuration updated successfully")
 			return nil
 		},
 	}
 }
+
+// selectTrunk offers the configured trunk, or the one detected from
+// origin/HEAD, ahead of the other local branches.
+func (svc localCommand) selectTrunk(configured string) (string, error) {
+	branches, err := svc.gitHelper.GetBranches()
+	if err != nil {
+		return "", log.Error("failed to get branches", err)
+	}
+
+	current := configured
+	if current == "" {
+		current, _ = svc.gitHelper.DetectTrunk()
+	}
+
+	return HandleSelectTrunk(branches, current)
+}
@@ -2,10 +2,11 @@ package createconfig
 
 import (
 	"github.com/pavlovic265/265-gt/config"
+	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/spf13/cobra"
 )
 
-func RegisterCommands(root *cobra.Command, r runner.Runner, cm config.ConfigManager) {
-	root.AddCommand(NewConfigCommand(r, cm).Command())
+func RegisterCommands(root *cobra.Command, r runner.Runner, cm config.ConfigManager, gh helpers.GitHelper) {
+	root.AddCommand(NewConfigCommand(r, cm, gh).Command())
 }
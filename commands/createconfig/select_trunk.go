@@ -0,0 +1,20 @@
+package createconfig
+
+import (
+	"github.com/pavlovic265/265-gt/ui/components"
+)
+
+// HandleSelectTrunk offers the local branches as trunk, listing current first.
+func HandleSelectTrunk(branches []string, current string) (string, error) {
+	choices := make([]string, 0, len(branches)+1)
+	if current != "" {
+		choices = append(choices, current)
+	}
+	for _, branch := range branches {
+		if branch != current {
+			choices = append(choices, branch)
+		}
+	}
+
+	return components.SelectString(choices)
+}
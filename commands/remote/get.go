@@ -0,0 +1,224 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pavlovic265/265-gt/client"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type getCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+	cliClient client.CliClient
+}
+
+// stackLink is one fetched branch and the branch its pull request targets.
+type stackLink struct {
+	branch string
+	parent string
+}
+
+func NewGetCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
+) getCommand {
+	return getCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+		cliClient: cliClient,
+	}
+}
+
+func (svc getCommand) Command() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "get <pr-number|branch>",
+		Short: "Fetch a pull request and the stack below it",
+		Long: "Follow the pull request's chain of base branches down to trunk, fetch every branch in it, " +
+			"create or update local branches and record their parents. Local commits that are not on " +
+			"the remote are never overwritten unless --force is given.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is in progress; run `gt cont` or `gt abort` first")
+			}
+
+			ctx := cmd.Context()
+			chain, err := svc.resolveChain(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := svc.fetch(chain); err != nil {
+				return err
+			}
+
+			if !force {
+				if err := svc.checkLocalCommits(chain); err != nil {
+					return err
+				}
+			}
+
+			if err := svc.gitHelper.BeginOperation("get"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
+
+			current, _ := svc.gitHelper.GetCurrentBranch()
+			for _, link := range chain {
+				if err := svc.updateBranch(link, current == link.branch); err != nil {
+					return err
+				}
+			}
+
+			top := chain[len(chain)-1].branch
+			if current != top {
+				if err := svc.runner.Git("checkout", "-q", top); err != nil {
+					return log.Error(fmt.Sprintf("failed to checkout %s", top), err)
+				}
+			}
+
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
+			}
+
+			log.Successf("Checked out %s with %d branches below it", top, len(chain)-1)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite local commits that are not on the remote")
+
+	return cmd
+}
+
+// resolveChain walks from the pull request down through the pull requests of
+// its base branches and returns the stack ordered from the bottom.
+func (svc getCommand) resolveChain(ctx context.Context, ref string) ([]stackLink, error) {
+	trunk, err := svc.gitHelper.GetTrunk(ctx)
+	if err != nil {
+		return nil, log.Error("failed to find trunk branch", err)
+	}
+
+	pr, err := svc.cliClient.GetPullRequest(ctx, ref)
+	if err != nil {
+		return nil, log.Error(fmt.Sprintf("failed to look up pull request %s", ref), err)
+	}
+	if pr == nil {
+		return nil, log.ErrorMsg(fmt.Sprintf("no open pull request for %s", ref))
+	}
+
+	var chain []stackLink
+	seen := map[string]bool{}
+	for pr != nil && !seen[pr.Branch] {
+		seen[pr.Branch] = true
+		chain = append(chain, stackLink{branch: pr.Branch, parent: pr.BaseBranch})
+
+		base := pr.BaseBranch
+		if base == trunk || svc.gitHelper.IsProtectedBranch(ctx, base) || seen[base] {
+			break
+		}
+
+		pr, err = svc.cliClient.GetPullRequest(ctx, base)
+		if err != nil {
+			return nil, log.Error(fmt.Sprintf("failed to look up pull request for %s", base), err)
+		}
+		if pr == nil {
+			log.Warningf("%s has no open pull request; tracking it on %s", base, trunk)
+			chain = append(chain, stackLink{branch: base, parent: trunk})
+		}
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+func (svc getCommand) fetch(chain []stackLink) error {
+	args := []string{"fetch", "-q", "origin"}
+	for _, link := range chain {
+		args = append(args, fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", link.branch, link.branch))
+	}
+	if err := svc.runner.Git(args...); err != nil {
+		return log.Error("failed to fetch branches", err)
+	}
+	return nil
+}
+
+// checkLocalCommits refuses to touch any branch that has commits the remote
+// does not, before a single branch is updated.
+func (svc getCommand) checkLocalCommits(chain []stackLink) error {
+	for _, link := range chain {
+		local := "refs/heads/" + link.branch
+		if _, err := svc.gitHelper.GetRevision(local); err != nil {
+			continue
+		}
+
+		ahead, err := svc.gitHelper.CountCommits("refs/remotes/origin/"+link.branch, local)
+		if err != nil {
+			return log.Error(fmt.Sprintf("failed to compare %s with origin", link.branch), err)
+		}
+		if ahead > 0 {
+			return log.ErrorMsg(fmt.Sprintf(
+				"branch %s has %d local commits that are not on origin; push them or use --force", link.branch, ahead))
+		}
+	}
+	return nil
+}
+
+func (svc getCommand) updateBranch(link stackLink, checkedOut bool) error {
+	remote := "refs/remotes/origin/" + link.branch
+	revision, err := svc.gitHelper.GetRevision(remote)
+	if err != nil {
+		return log.Error(fmt.Sprintf("failed to resolve origin/%s", link.branch), err)
+	}
+
+	local, err := svc.gitHelper.GetRevision("refs/heads/" + link.branch)
+	switch {
+	case err != nil:
+		if err := svc.runner.Git("branch", "-q", "--track", link.branch, "origin/"+link.branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to create branch %s", link.branch), err)
+		}
+		log.Infof("Created %s", link.branch)
+	case local == revision:
+	case checkedOut:
+		// --keep refuses instead of discarding uncommitted changes.
+		if err := svc.runner.Git("reset", "-q", "--keep", remote); err != nil {
+			return log.Error(fmt.Sprintf("failed to update %s", link.branch), err)
+		}
+		log.Infof("Updated %s", link.branch)
+	default:
+		if err := svc.runner.Git("update-ref", "refs/heads/"+link.branch, revision); err != nil {
+			return log.Error(fmt.Sprintf("failed to update %s", link.branch), err)
+		}
+		log.Infof("Updated %s", link.branch)
+	}
+
+	if err := svc.gitHelper.SetParent(link.parent, link.branch); err != nil {
+		return log.Error("failed to set parent branch relationship", err)
+	}
+
+	// Base the branch on where it forked from the remote parent, which is
+	// what the pull request shows even if the local parent is behind.
+	parentRef := link.parent
+	if _, err := svc.gitHelper.GetRevision("refs/remotes/origin/" + link.parent); err == nil {
+		parentRef = "refs/remotes/origin/" + link.parent
+	}
+	if forkPoint, err := svc.gitHelper.GetMergeBase(parentRef, remote); err == nil {
+		if err := svc.gitHelper.SetParentRevision(link.branch, forkPoint); err != nil {
+			return log.Error("failed to record parent revision", err)
+		}
+	}
+	return nil
+}
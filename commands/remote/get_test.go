@@ -0,0 +1,111 @@
+package remote_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/client"
+	"github.com/pavlovic265/265-gt/commands/remote"
+	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectStackLookup(
+	mockGitHelper *mocks.MockGitHelper, mockCliClient *clientmocks.MockCliClient, mockRunner *mocks.MockRunner,
+) {
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockCliClient.EXPECT().GetPullRequest(gomock.Any(), "12").
+		Return(&client.PullRequest{Number: 12, Branch: "feature-b", BaseBranch: "feature-a"}, nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockCliClient.EXPECT().GetPullRequest(gomock.Any(), "feature-a").
+		Return(&client.PullRequest{Number: 11, Branch: "feature-a", BaseBranch: "main"}, nil)
+	mockRunner.EXPECT().Git("fetch", "-q", "origin",
+		"+refs/heads/feature-a:refs/remotes/origin/feature-a",
+		"+refs/heads/feature-b:refs/remotes/origin/feature-b").Return(nil)
+}
+
+func TestGetCommand_FetchesStackAndRecordsParents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectStackLookup(mockGitHelper, mockCliClient, mockRunner)
+
+	// feature-a exists locally and is behind; feature-b is new.
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-a").Return("old-a", nil).Times(2)
+	mockGitHelper.EXPECT().CountCommits("refs/remotes/origin/feature-a", "refs/heads/feature-a").Return(0, nil)
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-b").Return("", errors.New("unknown revision")).Times(2)
+
+	mockGitHelper.EXPECT().BeginOperation("get").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("main", nil)
+
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/feature-a").Return("new-a", nil).Times(2)
+	mockRunner.EXPECT().Git("update-ref", "refs/heads/feature-a", "new-a").Return(nil)
+	mockGitHelper.EXPECT().SetParent("main", "feature-a").Return(nil)
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/main").Return("m1", nil)
+	mockGitHelper.EXPECT().GetMergeBase("refs/remotes/origin/main", "refs/remotes/origin/feature-a").Return("m0", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-a", "m0").Return(nil)
+
+	mockGitHelper.EXPECT().GetRevision("refs/remotes/origin/feature-b").Return("new-b", nil)
+	mockRunner.EXPECT().Git("branch", "-q", "--track", "feature-b", "origin/feature-b").Return(nil)
+	mockGitHelper.EXPECT().SetParent("feature-a", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().GetMergeBase("refs/remotes/origin/feature-a", "refs/remotes/origin/feature-b").
+		Return("new-a", nil)
+	mockGitHelper.EXPECT().SetParentRevision("feature-b", "new-a").Return(nil)
+
+	mockRunner.EXPECT().Git("checkout", "-q", "feature-b").Return(nil)
+
+	cmd := remote.NewGetCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	err := cmd.RunE(cmd, []string{"12"})
+
+	assert.NoError(t, err)
+}
+
+func TestGetCommand_RefusesLocalCommitsWithoutForce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	expectStackLookup(mockGitHelper, mockCliClient, mockRunner)
+
+	mockGitHelper.EXPECT().GetRevision("refs/heads/feature-a").Return("old-a", nil)
+	mockGitHelper.EXPECT().CountCommits("refs/remotes/origin/feature-a", "refs/heads/feature-a").Return(2, nil)
+
+	cmd := remote.NewGetCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	err := cmd.RunE(cmd, []string{"12"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--force")
+}
+
+func TestGetCommand_NoPullRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockCliClient.EXPECT().GetPullRequest(gomock.Any(), "scratch").Return(nil, nil)
+
+	cmd := remote.NewGetCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	err := cmd.RunE(cmd, []string{"scratch"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no open pull request")
+}
@@ -47,6 +47,10 @@ func (svc pushCommand) Command() *cobra.Command {
 				return log.Error("failed to push branch to remote", err)
 			}
 
+			if err := svc.gitHelper.PushMetadata(cmd.Context()); err != nil {
+				log.Warningf("failed to share stack metadata: %v", err)
+			}
+
 			hasOpenPR, err := svc.cliClient.HasOpenPullRequestForBranch(cmd.Context(), currentBranchName)
 			if err != nil {
 				return log.Error("failed to check for open pull request", err)
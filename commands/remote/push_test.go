@@ -53,6 +53,7 @@ func TestPushCommand_RunE_UpdatesOpenPullRequestBaseBranch(t *testing.T) {
 	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
 	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature/test", nil)
 	mockRunner.EXPECT().Git("push", "--force", "origin", "feature/test").Return(nil)
+	mockGitHelper.EXPECT().PushMetadata(gomock.Any()).Return(nil)
 	mockCliClient.EXPECT().
 		HasOpenPullRequestForBranch(gomock.Any(), "feature/test").
 		Return(true, nil)
@@ -80,6 +81,7 @@ func TestPushCommand_RunE_SkipsBaseBranchUpdateWhenNoOpenPullRequest(t *testing.
 	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
 	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature/test", nil)
 	mockRunner.EXPECT().Git("push", "--force", "origin", "feature/test").Return(nil)
+	mockGitHelper.EXPECT().PushMetadata(gomock.Any()).Return(nil)
 	mockCliClient.EXPECT().
 		HasOpenPullRequestForBranch(gomock.Any(), "feature/test").
 		Return(false, nil)
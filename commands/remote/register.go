@@ -11,4 +11,5 @@ func RegisterCommands(root *cobra.Command, r runner.Runner, gh helpers.GitHelper
 	root.AddCommand(NewPullCommand(r, gh).Command())
 	root.AddCommand(NewPushCommand(r, gh, cc).Command())
 	root.AddCommand(NewCloneCommand(r, gh).Command())
+	root.AddCommand(NewGetCommand(r, gh, cc).Command())
 }
@@ -0,0 +1,318 @@
+package stack
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/constants"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/components"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type fsckIssueKind string
+
+const (
+	issueStalePending  fsckIssueKind = "stale pending state"
+	issueOrphan        fsckIssueKind = "orphaned metadata"
+	issueSelfParent    fsckIssueKind = "self parent"
+	issueTrunkParent   fsckIssueKind = "trunk has parent"
+	issueMissingParent fsckIssueKind = "missing parent"
+	issueCycle         fsckIssueKind = "parent cycle"
+)
+
+type fsckIssue struct {
+	kind   fsckIssueKind
+	branch string
+	detail string
+}
+
+type fsckCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewFsckCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) fsckCommand {
+	return fsckCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+func (svc fsckCommand) Command() *cobra.Command {
+	var fix bool
+	var interactive bool
+
+	cmd := &cobra.Command{
+		Use:   "fsck",
+		Short: "Check stack metadata for stale, missing or cyclic parent links",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			trunk, err := svc.gitHelper.GetTrunk(cmd.Context())
+			if err != nil {
+				return log.Error("failed to find trunk branch", err)
+			}
+
+			branches, err := svc.gitHelper.GetBranches()
+			if err != nil {
+				return log.Error("failed to get branches", err)
+			}
+
+			issues := svc.findIssues(trunk, branches, svc.gitHelper.GetParents())
+			if len(issues) == 0 {
+				log.Success("Stack metadata is consistent")
+				return nil
+			}
+
+			for _, issue := range issues {
+				log.Warningf("%s: %s", issue.kind, issue.detail)
+			}
+
+			if !fix {
+				return log.ErrorMsg(fmt.Sprintf("found %d problems; run `gt fsck --fix` to repair them", len(issues)))
+			}
+
+			fmt.Println()
+			for _, issue := range issues {
+				if err := svc.fixIssue(issue, trunk, branches, interactive); err != nil {
+					return err
+				}
+			}
+
+			log.Successf("Repaired %d problems", len(issues))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Repair the problems found")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false,
+		"Choose the new parent of each broken branch instead of trunk")
+
+	return cmd
+}
+
+func (svc fsckCommand) findIssues(trunk string, branches []string, parents map[string]string) []fsckIssue {
+	var issues []fsckIssue
+
+	if issue, ok := svc.findStalePending(); ok {
+		issues = append(issues, issue)
+	}
+
+	exists := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		exists[branch] = true
+	}
+
+	tracked := make([]string, 0, len(parents))
+	live := make(map[string]string, len(parents))
+	for branch, parent := range parents {
+		tracked = append(tracked, branch)
+		if exists[branch] && branch != trunk {
+			live[branch] = parent
+		}
+	}
+	sort.Strings(tracked)
+
+	for _, branch := range tracked {
+		parent := parents[branch]
+		switch {
+		case !exists[branch]:
+			issues = append(issues, fsckIssue{
+				kind:   issueOrphan,
+				branch: branch,
+				detail: fmt.Sprintf("%s no longer exists but still has a parent (%s)", branch, parent),
+			})
+		case branch == trunk:
+			issues = append(issues, fsckIssue{
+				kind:   issueTrunkParent,
+				branch: branch,
+				detail: fmt.Sprintf("trunk %s is recorded as a child of %s", branch, parent),
+			})
+		case parent == branch:
+			issues = append(issues, fsckIssue{
+				kind:   issueSelfParent,
+				branch: branch,
+				detail: fmt.Sprintf("%s is recorded as its own parent", branch),
+			})
+		case !exists[parent]:
+			issues = append(issues, fsckIssue{
+				kind:   issueMissingParent,
+				branch: branch,
+				detail: fmt.Sprintf("parent %s of %s does not exist", parent, branch),
+			})
+		}
+	}
+
+	for _, cycle := range findCycles(live) {
+		issues = append(issues, fsckIssue{
+			kind:   issueCycle,
+			branch: slices.Min(cycle),
+			detail: strings.Join(append(cycle, cycle[0]), " → "),
+		})
+	}
+
+	return issues
+}
+
+func (svc fsckCommand) findStalePending() (fsckIssue, bool) {
+	if svc.gitHelper.IsRebaseInProgress() {
+		return fsckIssue{}, false
+	}
+
+	var stale []string
+	if parent, err := svc.gitHelper.GetPending(constants.ParentBranch); err == nil && parent != "" {
+		stale = append(stale, "gt.pending.parent")
+	}
+	if child, err := svc.gitHelper.GetPending(constants.ChildBranch); err == nil && child != "" {
+		stale = append(stale, "gt.pending.child")
+	}
+	if queue, err := svc.gitHelper.GetPendingQueue(); err == nil && len(queue) > 0 {
+		stale = append(stale, constants.GitConfigPendingQueue)
+	}
+	if chain, err := svc.gitHelper.GetPendingChain(); err == nil && len(chain) > 0 {
+		stale = append(stale, constants.GitConfigPendingChain)
+	}
+	if operation, err := svc.gitHelper.GetPendingOperation(); err == nil && operation != nil {
+		stale = append(stale, fmt.Sprintf("unfinished %s snapshot", operation.Operation))
+	}
+
+	if len(stale) == 0 {
+		return fsckIssue{}, false
+	}
+	return fsckIssue{
+		kind:   issueStalePending,
+		detail: "no rebase in progress but found " + strings.Join(stale, ", "),
+	}, true
+}
+
+// findCycles follows every parent chain and returns each loop once. Self
+// parents are reported separately and are not part of the result.
+func findCycles(parents map[string]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	names := make([]string, 0, len(parents))
+	for branch := range parents {
+		names = append(names, branch)
+	}
+	sort.Strings(names)
+
+	state := make(map[string]int, len(parents))
+	var cycles [][]string
+	for _, start := range names {
+		var path []string
+		node := start
+		for state[node] == unvisited {
+			parent, ok := parents[node]
+			if !ok || parent == node {
+				break
+			}
+			state[node] = visiting
+			path = append(path, node)
+			node = parent
+		}
+		if state[node] == visiting {
+			cycles = append(cycles, slices.Clone(path[slices.Index(path, node):]))
+		}
+		for _, branch := range path {
+			state[branch] = done
+		}
+		state[node] = done
+	}
+
+	return cycles
+}
+
+func (svc fsckCommand) fixIssue(issue fsckIssue, trunk string, branches []string, interactive bool) error {
+	switch issue.kind {
+	case issueStalePending:
+		_ = svc.gitHelper.DeletePending(constants.ParentBranch)
+		_ = svc.gitHelper.DeletePending(constants.ChildBranch)
+		if err := svc.gitHelper.DiscardOperation(); err != nil {
+			return log.Error("failed to clear pending state", err)
+		}
+		log.Success("Cleared stale pending state")
+	case issueOrphan:
+		if err := svc.gitHelper.DeleteParent(issue.branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to remove metadata of %s", issue.branch), err)
+		}
+		log.Successf("Removed metadata of deleted branch %s", issue.branch)
+	default:
+		return svc.reparent(issue.branch, trunk, branches, interactive)
+	}
+	return nil
+}
+
+func (svc fsckCommand) reparent(branch, trunk string, branches []string, interactive bool) error {
+	if branch == trunk {
+		if err := svc.gitHelper.DeleteParent(branch); err != nil {
+			return log.Error(fmt.Sprintf("failed to remove parent of %s", branch), err)
+		}
+		log.Successf("Removed parent of trunk %s", branch)
+		return nil
+	}
+
+	parent := trunk
+	if interactive {
+		log.Infof("Select a new parent for %s", branch)
+		selected, err := components.SelectString(svc.parentCandidates(branch, trunk, branches))
+		if err != nil {
+			return log.Error("failed to display branch selection", err)
+		}
+		if selected == "" {
+			log.Warningf("Skipped %s", branch)
+			return nil
+		}
+		parent = selected
+	}
+
+	if err := svc.gitHelper.SetParent(parent, branch); err != nil {
+		return log.Error("failed to set parent branch relationship", err)
+	}
+	if base, err := svc.gitHelper.GetMergeBase(parent, branch); err == nil {
+		if err := svc.gitHelper.SetParentRevision(branch, base); err != nil {
+			return log.Error("failed to record parent revision", err)
+		}
+	}
+
+	log.Successf("Re-parented %s onto %s", branch, parent)
+	return nil
+}
+
+// parentCandidates lists trunk first, then every branch that would not create a
+// cycle, i.e. anything but the branch itself and its descendants.
+func (svc fsckCommand) parentCandidates(branch, trunk string, branches []string) []string {
+	excluded := map[string]bool{branch: true}
+	queue := []string{branch}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range svc.gitHelper.GetChildren(current) {
+			if !excluded[child] {
+				excluded[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	candidates := []string{trunk}
+	for _, candidate := range branches {
+		if candidate != trunk && !excluded[candidate] {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,125 @@
+package stack_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/stack"
+	"github.com/pavlovic265/265-gt/constants"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectNoPendingState(mockGitHelper *mocks.MockGitHelper) {
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetPending(constants.ParentBranch).Return("", errors.New("not set"))
+	mockGitHelper.EXPECT().GetPending(constants.ChildBranch).Return("", errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingQueue().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingChain().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingOperation().Return(nil, nil)
+}
+
+func TestFsckCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := stack.NewFsckCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Equal(t, "fsck", cmd.Use)
+	assert.Equal(t, "Check stack metadata for stale, missing or cyclic parent links", cmd.Short)
+
+	fixFlag := cmd.Flags().Lookup("fix")
+	assert.NotNil(t, fixFlag)
+	assert.Equal(t, "false", fixFlag.DefValue)
+
+	interactiveFlag := cmd.Flags().Lookup("interactive")
+	assert.NotNil(t, interactiveFlag)
+	assert.Equal(t, "i", interactiveFlag.Shorthand)
+}
+
+func TestFsckCommand_RunE_Consistent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"feature-a", "main"}, nil)
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{"feature-a": "main"})
+	expectNoPendingState(mockGitHelper)
+
+	cmd := stack.NewFsckCommand(mockRunner, mockGitHelper).Command()
+
+	assert.NoError(t, cmd.RunE(cmd, nil))
+}
+
+func TestFsckCommand_RunE_ReportsProblemsWithoutFix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"feature-a", "main"}, nil)
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{"gone": "main"})
+	expectNoPendingState(mockGitHelper)
+
+	cmd := stack.NewFsckCommand(mockRunner, mockGitHelper).Command()
+
+	assert.Error(t, cmd.RunE(cmd, nil))
+}
+
+func TestFsckCommand_RunE_FixesProblems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockGitHelper.EXPECT().
+		GetBranches().
+		Return([]string{"cycle-a", "cycle-b", "feature-a", "main", "self"}, nil)
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{
+		"main":      "feature-a",
+		"gone":      "main",
+		"feature-a": "gone",
+		"self":      "self",
+		"cycle-a":   "cycle-b",
+		"cycle-b":   "cycle-a",
+	})
+
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetPending(constants.ParentBranch).Return("main", nil)
+	mockGitHelper.EXPECT().GetPending(constants.ChildBranch).Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetPendingQueue().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingChain().Return(nil, errors.New("not set"))
+	mockGitHelper.EXPECT().GetPendingOperation().Return(nil, nil)
+
+	mockGitHelper.EXPECT().DeletePending(constants.ParentBranch).Return(nil)
+	mockGitHelper.EXPECT().DeletePending(constants.ChildBranch).Return(nil)
+	mockGitHelper.EXPECT().DiscardOperation().Return(nil)
+
+	mockGitHelper.EXPECT().DeleteParent("gone").Return(nil)
+	mockGitHelper.EXPECT().DeleteParent("main").Return(nil)
+
+	for _, branch := range []string{"feature-a", "self", "cycle-a"} {
+		mockGitHelper.EXPECT().SetParent("main", branch).Return(nil)
+		mockGitHelper.EXPECT().GetMergeBase("main", branch).Return("base-"+branch, nil)
+		mockGitHelper.EXPECT().SetParentRevision(branch, "base-"+branch).Return(nil)
+	}
+
+	cmd := stack.NewFsckCommand(mockRunner, mockGitHelper).Command()
+	assert.NoError(t, cmd.Flags().Set("fix", "true"))
+
+	assert.NoError(t, cmd.RunE(cmd, nil))
+}
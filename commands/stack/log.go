@@ -0,0 +1,277 @@
+package stack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/ui/theme"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	treeBranch = "├── "
+	treeLast   = "└── "
+	treePipe   = "│   "
+	treeSpace  = "    "
+)
+
+var (
+	currentBranchStyle = lipgloss.NewStyle().
+				Foreground(theme.Green).
+				Bold(true)
+
+	treeBranchStyle = lipgloss.NewStyle().
+			Foreground(theme.Magenta)
+
+	treeLineStyle = lipgloss.NewStyle().
+			Foreground(theme.BrightBlack)
+
+	aheadStyle = lipgloss.NewStyle().
+			Foreground(theme.Cyan)
+
+	restackStyle = lipgloss.NewStyle().
+			Foreground(theme.Yellow)
+
+	commitStyle = lipgloss.NewStyle().
+			Foreground(theme.BrightBlack)
+)
+
+type logCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+}
+
+func NewLogCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+) logCommand {
+	return logCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+	}
+}
+
+// Command is `gt log`. It only takes over `git log` when called bare or with
+// -l/--long; any other arguments are passed through to git.
+func (svc logCommand) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show the branch stack as a tree",
+		Long: "Show the branch stack as a tree, or list each branch's commits with -l/--long. " +
+			"Any other arguments are passed through to `git log`.",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 && (args[0] == "-h" || args[0] == "--help") {
+				return cmd.Help()
+			}
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			switch {
+			case len(args) == 0:
+				return svc.printStacks(false)
+			case len(args) == 1 && (args[0] == "-l" || args[0] == "--long"):
+				return svc.printStacks(true)
+			}
+			return svc.runner.Git(append([]string{"log"}, args...)...)
+		},
+	}
+
+	// Parsed by hand above; declared so it shows up in help and completion.
+	cmd.Flags().BoolP("long", "l", false, "List the commits of each branch")
+
+	return cmd
+}
+
+// TreeCommand is `gt stack tree`, the same view under the stack commands.
+func (svc logCommand) TreeCommand() *cobra.Command {
+	var long bool
+
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Show the branch stack as a tree",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+			return svc.printStacks(long)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&long, "long", "l", false, "List the commits of each branch")
+
+	return cmd
+}
+
+func (svc logCommand) ShortCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "Show the branch stack as a tree (short)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+			return svc.printStacks(false)
+		},
+	}
+}
+
+func (svc logCommand) LongCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ll",
+		Short: "Show the branch stack as a tree with commits",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+			return svc.printStacks(true)
+		},
+	}
+}
+
+type stackGraph struct {
+	branches []string
+	parents  map[string]string
+	children map[string][]string
+}
+
+func (svc logCommand) loadGraph() (stackGraph, error) {
+	branches, err := svc.gitHelper.GetBranches()
+	if err != nil {
+		return stackGraph{}, log.Error("failed to get branches", err)
+	}
+
+	graph := stackGraph{
+		branches: branches,
+		parents:  make(map[string]string),
+		children: make(map[string][]string),
+	}
+	for _, branch := range branches {
+		parent, err := svc.gitHelper.GetParent(branch)
+		if err != nil || parent == "" || parent == branch {
+			continue
+		}
+		graph.parents[branch] = parent
+		graph.children[parent] = append(graph.children[parent], branch)
+	}
+
+	return graph, nil
+}
+
+func (g stackGraph) roots() []string {
+	exists := make(map[string]bool, len(g.branches))
+	for _, branch := range g.branches {
+		exists[branch] = true
+	}
+
+	var roots []string
+	for _, branch := range g.branches {
+		parent, tracked := g.parents[branch]
+		if tracked && exists[parent] {
+			continue
+		}
+		if tracked || len(g.children[branch]) > 0 {
+			roots = append(roots, branch)
+		}
+	}
+	return roots
+}
+
+func (svc logCommand) printStacks(long bool) error {
+	currentBranch, err := svc.gitHelper.GetCurrentBranch()
+	if err != nil {
+		return log.Error("failed to get current branch name", err)
+	}
+
+	graph, err := svc.loadGraph()
+	if err != nil {
+		return err
+	}
+
+	roots := graph.roots()
+	if len(roots) == 0 {
+		log.Info("No stacks found. Create one with `gt create <branch>`")
+		return nil
+	}
+
+	var out strings.Builder
+	visited := make(map[string]bool)
+	for _, root := range roots {
+		svc.writeBranch(&out, graph, root, currentBranch, "", "", long, visited)
+	}
+	fmt.Print(out.String())
+
+	return nil
+}
+
+func (svc logCommand) writeBranch(
+	out *strings.Builder,
+	graph stackGraph,
+	branch, currentBranch, linePrefix, childPrefix string,
+	long bool,
+	visited map[string]bool,
+) {
+	if visited[branch] {
+		return
+	}
+	visited[branch] = true
+
+	out.WriteString(treeLineStyle.Render(linePrefix))
+	out.WriteString(svc.formatBranch(graph, branch, currentBranch))
+	out.WriteString("\n")
+
+	children := graph.children[branch]
+	if long {
+		if parent, ok := graph.parents[branch]; ok {
+			commitPrefix := childPrefix + treePipe
+			if len(children) == 0 {
+				commitPrefix = childPrefix + treeSpace
+			}
+			commits, _ := svc.gitHelper.GetCommits(parent, branch)
+			for _, commit := range commits {
+				out.WriteString(treeLineStyle.Render(commitPrefix))
+				out.WriteString(commitStyle.Render(commit))
+				out.WriteString("\n")
+			}
+		}
+	}
+
+	for i, child := range children {
+		connector, next := treeBranch, treePipe
+		if i == len(children)-1 {
+			connector, next = treeLast, treeSpace
+		}
+		svc.writeBranch(out, graph, child, currentBranch,
+			childPrefix+connector, childPrefix+next, long, visited)
+	}
+}
+
+func (svc logCommand) formatBranch(graph stackGraph, branch, currentBranch string) string {
+	var line strings.Builder
+	if branch == currentBranch {
+		line.WriteString(currentBranchStyle.Render("◉ " + branch))
+	} else {
+		line.WriteString(treeBranchStyle.Render("◯ " + branch))
+	}
+
+	parent, ok := graph.parents[branch]
+	if !ok {
+		return line.String()
+	}
+
+	if ahead, err := svc.gitHelper.CountCommits(parent, branch); err == nil {
+		line.WriteString(" ")
+		line.WriteString(aheadStyle.Render(fmt.Sprintf("+%d", ahead)))
+	}
+	if svc.gitHelper.NeedsRestack(branch, parent) {
+		line.WriteString(" ")
+		line.WriteString(restackStyle.Render(theme.WarningIcon + " needs restack"))
+	}
+
+	return line.String()
+}
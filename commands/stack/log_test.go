@@ -0,0 +1,102 @@
+package stack_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/commands/stack"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	logCmd := stack.NewLogCommand(mockRunner, mockGitHelper)
+	cmd := logCmd.Command()
+
+	assert.Equal(t, "log", cmd.Use)
+	assert.Equal(t, "Show the branch stack as a tree", cmd.Short)
+
+	longFlag := cmd.Flags().Lookup("long")
+	assert.NotNil(t, longFlag)
+	assert.Equal(t, "l", longFlag.Shorthand)
+	assert.Equal(t, "false", longFlag.DefValue)
+}
+
+func TestLogCommand_ShortAndLongCommands(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	logCmd := stack.NewLogCommand(mockRunner, mockGitHelper)
+
+	assert.Equal(t, "ls", logCmd.ShortCommand().Use)
+	assert.Equal(t, "ll", logCmd.LongCommand().Use)
+}
+
+func TestLogCommand_RunE_RendersStack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"feature-a", "feature-b", "main"}, nil)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("feature-a", nil)
+	mockGitHelper.EXPECT().GetParent("main").Return("", errors.New("not found"))
+	mockGitHelper.EXPECT().CountCommits("main", "feature-a").Return(2, nil)
+	mockGitHelper.EXPECT().NeedsRestack("feature-a", "main").Return(false)
+	mockGitHelper.EXPECT().CountCommits("feature-a", "feature-b").Return(1, nil)
+	mockGitHelper.EXPECT().NeedsRestack("feature-b", "feature-a").Return(true)
+	mockGitHelper.EXPECT().GetCommits("main", "feature-a").Return([]string{"abc123 first"}, nil)
+	mockGitHelper.EXPECT().GetCommits("feature-a", "feature-b").Return([]string{"def456 second"}, nil)
+
+	cmd := stack.NewLogCommand(mockRunner, mockGitHelper).LongCommand()
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestLogCommand_RunE_PassesGitLogArgumentsThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockRunner.EXPECT().Git("log", "--oneline", "-5").Return(nil)
+
+	cmd := stack.NewLogCommand(mockRunner, mockGitHelper).Command()
+
+	if err := cmd.RunE(cmd, []string{"--oneline", "-5"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestLogCommand_TreeCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	cmd := stack.NewLogCommand(mockRunner, mockGitHelper).TreeCommand()
+
+	assert.Equal(t, "tree", cmd.Use)
+	longFlag := cmd.Flags().Lookup("long")
+	assert.NotNil(t, longFlag)
+	assert.Equal(t, "l", longFlag.Shorthand)
+}
@@ -10,4 +10,11 @@ import (
 func RegisterCommands(root *cobra.Command, r runner.Runner, gh helpers.GitHelper, cc client.CliClient) {
 	root.AddCommand(NewStackCommand(r, gh).Command())
 	root.AddCommand(NewSubmitCommand(r, gh, cc).Command())
+	root.AddCommand(NewSyncCommand(r, gh, cc).Command())
+	root.AddCommand(NewFsckCommand(r, gh).Command())
+
+	logCmd := NewLogCommand(r, gh)
+	root.AddCommand(logCmd.Command())
+	root.AddCommand(logCmd.ShortCommand())
+	root.AddCommand(logCmd.LongCommand())
 }
@@ -1,6 +1,12 @@
 package stack
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
 	helpers "github.com/pavlovic265/265-gt/helpers"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/utils/log"
@@ -23,43 +29,225 @@ func NewRestackCommand(
 }
 
 func (svc restackCommand) Command() *cobra.Command {
-	return &cobra.Command{
+	var (
+		upstack   bool
+		downstack bool
+		stack     bool
+		all       bool
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
 		Use:     "restack",
 		Aliases: []string{"rs"},
 		Short:   "Restack branches",
+		Long: "Rebase branches onto their parents, from the current branch upward by default. " +
+			"--downstack restacks from trunk up to the current branch, --stack the whole stack " +
+			"containing it and --all every tracked stack. Branches already on their parent's tip are skipped. " +
+			"--dry-run predicts which branches would conflict without checking anything out.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
 				return err
 			}
 
+			ctx := cmd.Context()
 			branch, err := svc.gitHelper.GetCurrentBranch()
 			if err != nil {
 				return err
 			}
-			queue := []string{branch}
-			for len(queue) > 0 {
-				parent := queue[0]
-				queue = queue[1:]
 
-				children := svc.gitHelper.GetChildren(parent)
-
-				for _, child := range children {
-					if child == parent {
-						continue
+			// Stacks grow from the trunk; an untracked branch elsewhere has no
+			// stack worth restacking.
+			if !all && !svc.gitHelper.IsProtectedBranch(ctx, branch) {
+				if parent, err := svc.gitHelper.GetParent(branch); err != nil || parent == "" {
+					trunk, err := svc.gitHelper.GetTrunk(ctx)
+					if err != nil {
+						return log.Error("failed to find trunk branch", err)
 					}
+					return log.ErrorMsg(fmt.Sprintf(
+						"branch %s is not tracked; run `gt track` or switch to %s", branch, trunk))
+				}
+			}
 
-					if err := svc.gitHelper.RebaseBranch(child, parent); err != nil {
-						return err
-					}
+			var queue []string
+			switch {
+			case downstack:
+				queue = svc.downstack(ctx, branch)
+			case stack:
+				queue = svc.upstack(ctx, svc.bottom(ctx, branch))
+			case all:
+				if queue, err = svc.roots(); err != nil {
+					return err
+				}
+			default:
+				queue = svc.upstack(ctx, branch)
+			}
+
+			if dryRun {
+				if !downstack {
+					queue = svc.gitHelper.GetDescendants(queue)
+				}
+				return svc.predict(queue)
+			}
 
-					queue = append(queue, child)
+			if err := svc.gitHelper.BeginOperation("restack"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
 
+			var restacked int
+			if downstack {
+				restacked, err = svc.gitHelper.RestackChain(queue)
+			} else {
+				restacked, err = svc.gitHelper.RestackBranches(queue)
+			}
+			if err != nil {
+				return err
+			}
+
+			if restacked > 0 {
+				if err := svc.runner.Git("checkout", "-q", branch); err != nil {
+					return log.Error(fmt.Sprintf("failed to checkout %s", branch), err)
 				}
+			}
 
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
 			}
 
-			log.Success("Restack completed")
+			if restacked == 0 {
+				log.Success("Restack completed, every branch is already on its parent")
+				return nil
+			}
+			log.Successf("Restack completed, %d branches rebased", restacked)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&upstack, "upstack", false, "Restack the current branch and its descendants (default)")
+	cmd.Flags().BoolVar(&downstack, "downstack", false, "Restack from trunk up to the current branch")
+	cmd.Flags().BoolVar(&stack, "stack", false, "Restack the whole stack containing the current branch")
+	cmd.Flags().BoolVar(&all, "all", false, "Restack every tracked stack")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Report which branches would conflict without restacking")
+	cmd.MarkFlagsMutuallyExclusive("upstack", "downstack", "stack", "all")
+
+	return cmd
+}
+
+func (svc restackCommand) isTracked(ctx context.Context, branch string) bool {
+	if svc.gitHelper.IsProtectedBranch(ctx, branch) {
+		return false
+	}
+	parent, err := svc.gitHelper.GetParent(branch)
+	return err == nil && parent != "" && parent != branch
+}
+
+// upstack is the queue that restacks branch and everything above it; a
+// protected branch is never rebased itself, only its children.
+func (svc restackCommand) upstack(ctx context.Context, branch string) []string {
+	if svc.isTracked(ctx, branch) {
+		return []string{branch}
+	}
+	return svc.gitHelper.GetChildren(branch)
+}
+
+// downstack lists the branches from the bottom of the stack up to branch.
+func (svc restackCommand) downstack(ctx context.Context, branch string) []string {
+	var chain []string
+	seen := map[string]bool{}
+	for svc.isTracked(ctx, branch) && !seen[branch] {
+		seen[branch] = true
+		chain = append(chain, branch)
+		branch, _ = svc.gitHelper.GetParent(branch)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// bottom returns the first branch above trunk in branch's stack, or branch
+// itself when it is not part of a stack.
+func (svc restackCommand) bottom(ctx context.Context, branch string) string {
+	if chain := svc.downstack(ctx, branch); len(chain) > 0 {
+		return chain[0]
+	}
+	return branch
+}
+
+// roots returns every tracked branch whose parent is not tracked itself, the
+// bottoms of all stacks in the repository. Keys left behind by deleted
+// branches are ignored, and branches whose parent was deleted are skipped.
+func (svc restackCommand) roots() ([]string, error) {
+	branches, err := svc.gitHelper.GetBranches()
+	if err != nil {
+		return nil, log.Error("failed to get branches", err)
+	}
+	exists := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		exists[branch] = true
+	}
+
+	parents := svc.gitHelper.GetParents()
+	var roots []string
+	for branch, parent := range parents {
+		if parent == branch || !exists[branch] {
+			continue
+		}
+		if !exists[parent] {
+			log.Warningf("Skipping %s: its parent %s no longer exists; run `gt fsck --fix`", branch, parent)
+			continue
+		}
+		if _, tracked := parents[parent]; !tracked {
+			roots = append(roots, branch)
+		}
+	}
+	sort.Strings(roots)
+	return roots, nil
+}
+
+// predict simulates the restack of every branch in order, each against its
+// parent's simulated tip, and reports the outcome without touching the worktree.
+func (svc restackCommand) predict(order []string) error {
+	simulated := make(map[string]string)
+	total, conflicting := 0, 0
+
+	for _, branch := range order {
+		parent, err := svc.gitHelper.GetParent(branch)
+		if err != nil || parent == "" || parent == branch {
+			continue
+		}
+
+		onto, ok := simulated[parent]
+		if !ok {
+			if onto, err = svc.gitHelper.GetRevision(parent); err != nil {
+				return log.Error(fmt.Sprintf("failed to resolve %s", parent), err)
+			}
+		}
+
+		revision, err := svc.gitHelper.GetRevision(branch)
+		if err != nil {
+			return log.Error(fmt.Sprintf("failed to resolve %s", branch), err)
+		}
+
+		tip, conflicts, err := svc.gitHelper.SimulateRebase(branch, parent, onto)
+		if err != nil {
+			return log.Error("failed to simulate restack", err)
+		}
+		simulated[branch] = tip
+		total++
+
+		switch {
+		case tip == revision:
+			log.Infof("%s is up to date with %s", branch, parent)
+		case len(conflicts) > 0:
+			conflicting++
+			log.Warningf("%s conflicts with %s in %s", branch, parent, strings.Join(conflicts, ", "))
+		default:
+			log.Successf("%s restacks cleanly onto %s", branch, parent)
+		}
+	}
+
+	if conflicting > 0 {
+		log.Warningf("%d of %d branches would conflict", conflicting, total)
+	}
+	return nil
 }
@@ -1,6 +1,8 @@
 package stack_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -37,3 +39,135 @@ func TestNewRestackCommand(t *testing.T) {
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "restack", cmd.Use)
 }
+
+func TestRestackCommand_UntrackedBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("scratch", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "scratch").Return(false)
+	mockGitHelper.EXPECT().GetParent("scratch").Return("", errors.New("no parent recorded"))
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("develop", nil)
+
+	cmd := stack.NewRestackCommand(mockRunner, mockGitHelper).Command()
+	err := cmd.RunE(cmd, []string{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "switch to develop")
+}
+
+func TestRestackCommand_Downstack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	parents := map[string]string{"feature-a": "main", "feature-b": "feature-a", "feature-c": "feature-b"}
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-c", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, branch string) bool { return branch == "main" }).AnyTimes()
+	mockGitHelper.EXPECT().GetParent(gomock.Any()).DoAndReturn(
+		func(branch string) (string, error) { return parents[branch], nil }).AnyTimes()
+	mockGitHelper.EXPECT().BeginOperation("restack").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().RestackChain([]string{"feature-a", "feature-b", "feature-c"}).Return(2, nil)
+	mockRunner.EXPECT().Git("checkout", "-q", "feature-c").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := stack.NewRestackCommand(mockRunner, mockGitHelper).Command()
+	_ = cmd.Flags().Set("downstack", "true")
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestRestackCommand_StackStartsAtBottom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	parents := map[string]string{"feature-a": "main", "feature-b": "feature-a"}
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, branch string) bool { return branch == "main" }).AnyTimes()
+	mockGitHelper.EXPECT().GetParent(gomock.Any()).DoAndReturn(
+		func(branch string) (string, error) { return parents[branch], nil }).AnyTimes()
+	mockGitHelper.EXPECT().BeginOperation("restack").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-a"}).Return(0, nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := stack.NewRestackCommand(mockRunner, mockGitHelper).Command()
+	_ = cmd.Flags().Set("stack", "true")
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestRestackCommand_AllStartsAtEveryStackBottom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("scratch", nil)
+	mockGitHelper.EXPECT().GetBranches().
+		Return([]string{"main", "release", "scratch", "feature-a", "feature-b", "hotfix", "orphan"}, nil)
+	// ghost was deleted without cleaning its keys; orphan was stacked on it.
+	mockGitHelper.EXPECT().GetParents().Return(map[string]string{
+		"feature-a": "main",
+		"feature-b": "feature-a",
+		"hotfix":    "release",
+		"ghost":     "main",
+		"orphan":    "ghost",
+	})
+	mockGitHelper.EXPECT().BeginOperation("restack").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-a", "hotfix"}).Return(1, nil)
+	mockRunner.EXPECT().Git("checkout", "-q", "scratch").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := stack.NewRestackCommand(mockRunner, mockGitHelper).Command()
+	_ = cmd.Flags().Set("all", "true")
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
+
+func TestRestackCommand_DryRunChainsSimulatedTips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+
+	parents := map[string]string{"feature-a": "main", "feature-b": "feature-a"}
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-a", nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	mockGitHelper.EXPECT().GetParent(gomock.Any()).DoAndReturn(
+		func(branch string) (string, error) { return parents[branch], nil }).AnyTimes()
+	mockGitHelper.EXPECT().GetDescendants([]string{"feature-a"}).Return([]string{"feature-a", "feature-b"})
+	mockGitHelper.EXPECT().GetRevision("main").Return("m1", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-a").Return("a1", nil)
+	mockGitHelper.EXPECT().GetRevision("feature-b").Return("b1", nil)
+	mockGitHelper.EXPECT().SimulateRebase("feature-a", "main", "m1").Return("a2", nil, nil)
+	mockGitHelper.EXPECT().SimulateRebase("feature-b", "feature-a", "a2").Return("b2", []string{"login.go"}, nil)
+
+	cmd := stack.NewRestackCommand(mockRunner, mockGitHelper).Command()
+	_ = cmd.Flags().Set("dry-run", "true")
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+}
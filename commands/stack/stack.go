@@ -29,6 +29,7 @@ func (svc stackCommand) Command() *cobra.Command {
 	}
 
 	stackCmd.AddCommand(NewRestackCommand(svc.runner, svc.gitHelper).Command())
+	stackCmd.AddCommand(NewLogCommand(svc.runner, svc.gitHelper).TreeCommand())
 
 	return stackCmd
 }
@@ -49,6 +49,7 @@ func TestStackCommand_HasSubcommands(t *testing.T) {
 	cmd := stackCmd.Command()
 
 	assert.True(t, cmd.HasSubCommands())
-	assert.Len(t, cmd.Commands(), 1)
+	assert.Len(t, cmd.Commands(), 2)
 	assert.Equal(t, "restack", cmd.Commands()[0].Use)
+	assert.Equal(t, "tree", cmd.Commands()[1].Use)
 }
@@ -67,6 +67,11 @@ func (svc submitCommand) Command() *cobra.Command {
 				return err
 			}
 
+			if err := svc.gitHelper.BeginOperation("submit-stack"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
+
 			queue := []string{originalBranch}
 			submitted := 0
 			created := 0
@@ -139,6 +144,14 @@ func (svc submitCommand) Command() *cobra.Command {
 				return log.Error("failed to checkout original branch", err)
 			}
 
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
+			}
+
+			if err := svc.gitHelper.PushMetadata(cmd.Context()); err != nil {
+				log.Warningf("failed to share stack metadata: %v", err)
+			}
+
 			log.Successf(
 				"Submit stack completed: %d pushed, %d PRs created", submitted, created,
 			)
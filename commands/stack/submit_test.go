@@ -103,6 +103,9 @@ func TestSubmitCommand_RunE_UpdatesBaseBranchForExistingPR(t *testing.T) {
 		ListPullRequests(gomock.Any(), []string{}).
 		Return([]client.PullRequest{{Branch: "feature/test"}}, nil)
 	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature/test", nil)
+	mockGitHelper.EXPECT().BeginOperation("submit-stack").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
 	mockRunner.EXPECT().Git("checkout", "feature/test").Return(nil)
 	mockRunner.EXPECT().Git("push", "--force", "origin", "feature/test").Return(nil)
 	mockCliClient.EXPECT().
@@ -110,6 +113,7 @@ func TestSubmitCommand_RunE_UpdatesBaseBranchForExistingPR(t *testing.T) {
 		Return(nil)
 	mockGitHelper.EXPECT().GetChildren("feature/test").Return(nil)
 	mockRunner.EXPECT().Git("checkout", "feature/test").Return(nil)
+	mockGitHelper.EXPECT().PushMetadata(gomock.Any()).Return(nil)
 
 	cmd := stack.NewSubmitCommand(mockRunner, mockGitHelper, mockCliClient).Command()
 	cmd.SetContext(testCommandContext())
@@ -133,6 +137,9 @@ func TestSubmitCommand_RunE_CreatesPRWhenMissing(t *testing.T) {
 		ListPullRequests(gomock.Any(), []string{}).
 		Return(nil, nil)
 	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature/test", nil)
+	mockGitHelper.EXPECT().BeginOperation("submit-stack").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
 	mockRunner.EXPECT().Git("checkout", "feature/test").Return(nil)
 	mockRunner.EXPECT().Git("push", "--force", "origin", "feature/test").Return(nil)
 	mockCliClient.EXPECT().
@@ -140,6 +147,7 @@ func TestSubmitCommand_RunE_CreatesPRWhenMissing(t *testing.T) {
 		Return(nil)
 	mockGitHelper.EXPECT().GetChildren("feature/test").Return(nil)
 	mockRunner.EXPECT().Git("checkout", "feature/test").Return(nil)
+	mockGitHelper.EXPECT().PushMetadata(gomock.Any()).Return(nil)
 
 	cmd := stack.NewSubmitCommand(mockRunner, mockGitHelper, mockCliClient).Command()
 	cmd.SetContext(testCommandContext())
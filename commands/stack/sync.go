@@ -0,0 +1,183 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pavlovic265/265-gt/client"
+	helpers "github.com/pavlovic265/265-gt/helpers"
+	"github.com/pavlovic265/265-gt/runner"
+	"github.com/pavlovic265/265-gt/utils/log"
+	"github.com/spf13/cobra"
+)
+
+type syncCommand struct {
+	runner    runner.Runner
+	gitHelper helpers.GitHelper
+	cliClient client.CliClient
+}
+
+func NewSyncCommand(
+	runner runner.Runner,
+	gitHelper helpers.GitHelper,
+	cliClient client.CliClient,
+) syncCommand {
+	return syncCommand{
+		runner:    runner,
+		gitHelper: gitHelper,
+		cliClient: cliClient,
+	}
+}
+
+func (svc syncCommand) Command() *cobra.Command {
+	var noDelete bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Update trunk, delete merged branches and restack all stacks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.gitHelper.EnsureGitRepository(); err != nil {
+				return err
+			}
+
+			if svc.gitHelper.IsRebaseInProgress() {
+				return log.ErrorMsg("a rebase is already in progress; resolve it, then run `gt cont` or abort")
+			}
+
+			originalBranch, err := svc.gitHelper.GetCurrentBranch()
+			if err != nil {
+				return log.Error("failed to get current branch name", err)
+			}
+
+			trunk, err := svc.gitHelper.GetTrunk(cmd.Context())
+			if err != nil {
+				return log.Error("failed to find trunk branch", err)
+			}
+
+			if err := svc.gitHelper.BeginOperation("sync"); err != nil {
+				return log.Error("failed to record operation", err)
+			}
+			defer svc.gitHelper.SettleOperation()
+
+			if err := svc.updateTrunk(trunk, originalBranch); err != nil {
+				return err
+			}
+
+			if updated, err := svc.gitHelper.PullMetadata(cmd.Context()); err != nil {
+				log.Warningf("failed to fetch shared stack metadata: %v", err)
+			} else if updated > 0 {
+				log.Infof("Updated %d branches from shared stack metadata", updated)
+			}
+
+			var deleted []string
+			if !noDelete {
+				deleted, err = svc.deleteFinishedBranches(cmd.Context(), trunk, originalBranch)
+				if err != nil {
+					return err
+				}
+			}
+
+			restacked, err := svc.gitHelper.RestackBranches(svc.gitHelper.GetChildren(trunk))
+			if err != nil {
+				return err
+			}
+
+			returnTo := originalBranch
+			for _, branch := range deleted {
+				if branch == originalBranch {
+					returnTo = trunk
+				}
+			}
+			if err := svc.runner.Git("checkout", returnTo); err != nil {
+				return log.Error(fmt.Sprintf("failed to checkout branch %s", returnTo), err)
+			}
+
+			if err := svc.gitHelper.EndOperation(); err != nil {
+				return log.Error("failed to finish operation", err)
+			}
+
+			svc.printSummary(trunk, deleted, restacked)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noDelete, "no-delete", false, "Do not delete branches whose pull requests are merged or closed")
+
+	return cmd
+}
+
+func (svc syncCommand) updateTrunk(trunk, currentBranch string) error {
+	log.Infof("Updating %s from origin", trunk)
+
+	if currentBranch == trunk {
+		if err := svc.runner.Git("pull", "--ff-only", "origin", trunk); err != nil {
+			return log.Error(fmt.Sprintf("failed to fast-forward %s", trunk), err)
+		}
+		return nil
+	}
+
+	if err := svc.runner.Git("fetch", "origin", trunk+":"+trunk); err != nil {
+		return log.Error(fmt.Sprintf("failed to fast-forward %s", trunk), err)
+	}
+	return nil
+}
+
+func (svc syncCommand) deleteFinishedBranches(
+	ctx context.Context, trunk, currentBranch string,
+) ([]string, error) {
+	branches, err := svc.gitHelper.GetBranches()
+	if err != nil {
+		return nil, log.Error("failed to get branches", err)
+	}
+
+	var deleted []string
+	for _, branch := range branches {
+		if branch == trunk || svc.gitHelper.IsProtectedBranch(ctx, branch) {
+			continue
+		}
+
+		parent, err := svc.gitHelper.GetParent(branch)
+		if err != nil || parent == "" {
+			continue
+		}
+
+		state, err := svc.cliClient.GetPullRequestState(ctx, branch)
+		if err != nil {
+			log.Warningf("failed to check pull request for %s: %v", branch, err)
+			continue
+		}
+		if state != client.PullRequestStateMerged && state != client.PullRequestStateClosed {
+			continue
+		}
+
+		if branch == currentBranch {
+			if err := svc.runner.Git("checkout", trunk); err != nil {
+				return deleted, log.Error(fmt.Sprintf("failed to checkout branch %s", trunk), err)
+			}
+			currentBranch = trunk
+		}
+
+		children := svc.gitHelper.GetChildren(branch)
+		if err := svc.runner.Git("branch", "-D", branch); err != nil {
+			return deleted, log.Error(fmt.Sprintf("failed to delete branch %s", branch), err)
+		}
+		if err := svc.gitHelper.RelinkParentChildren(parent, children); err != nil {
+			return deleted, log.Error("failed to update branch relationships", err)
+		}
+		_ = svc.gitHelper.DeleteParent(branch)
+
+		log.Successf("Deleted %s (pull request %s)", branch, state)
+		deleted = append(deleted, branch)
+	}
+
+	return deleted, nil
+}
+
+func (svc syncCommand) printSummary(trunk string, deleted []string, restacked int) {
+	fmt.Println()
+	log.Successf("Sync completed: %s updated, %d branches deleted, %d branches restacked",
+		trunk, len(deleted), restacked)
+	for _, branch := range deleted {
+		log.Infof("  deleted %s", branch)
+	}
+}
@@ -0,0 +1,79 @@
+package stack_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/client"
+	"github.com/pavlovic265/265-gt/commands/stack"
+	"github.com/pavlovic265/265-gt/mocks"
+	clientmocks "github.com/pavlovic265/265-gt/mocks/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncCommand_Command(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	syncCmd := stack.NewSyncCommand(mockRunner, mockGitHelper, mockCliClient)
+	cmd := syncCmd.Command()
+
+	assert.Equal(t, "sync", cmd.Use)
+	assert.Equal(t, "Update trunk, delete merged branches and restack all stacks", cmd.Short)
+
+	noDeleteFlag := cmd.Flags().Lookup("no-delete")
+	assert.NotNil(t, noDeleteFlag)
+	assert.Equal(t, "false", noDeleteFlag.DefValue)
+}
+
+func TestSyncCommand_RunE_DeletesMergedBranchAndRestacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	mockGitHelper := mocks.NewMockGitHelper(ctrl)
+	mockCliClient := clientmocks.NewMockCliClient(ctrl)
+
+	mockGitHelper.EXPECT().EnsureGitRepository().Return(nil)
+	mockGitHelper.EXPECT().IsRebaseInProgress().Return(false)
+	mockGitHelper.EXPECT().GetCurrentBranch().Return("feature-b", nil)
+	mockGitHelper.EXPECT().GetTrunk(gomock.Any()).Return("main", nil)
+	mockGitHelper.EXPECT().BeginOperation("sync").Return(nil)
+	mockGitHelper.EXPECT().SettleOperation().Return(nil)
+	mockRunner.EXPECT().Git("fetch", "origin", "main:main").Return(nil)
+	mockGitHelper.EXPECT().PullMetadata(gomock.Any()).Return(0, nil)
+
+	mockGitHelper.EXPECT().GetBranches().Return([]string{"feature-a", "feature-b", "main"}, nil)
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-a").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-a").Return("main", nil)
+	mockCliClient.EXPECT().
+		GetPullRequestState(gomock.Any(), "feature-a").
+		Return(client.PullRequestStateMerged, nil)
+	mockGitHelper.EXPECT().GetChildren("feature-a").Return([]string{"feature-b"})
+	mockRunner.EXPECT().Git("branch", "-D", "feature-a").Return(nil)
+	mockGitHelper.EXPECT().RelinkParentChildren("main", []string{"feature-b"}).Return(nil)
+	mockGitHelper.EXPECT().DeleteParent("feature-a").Return(nil)
+
+	mockGitHelper.EXPECT().IsProtectedBranch(gomock.Any(), "feature-b").Return(false)
+	mockGitHelper.EXPECT().GetParent("feature-b").Return("main", nil)
+	mockCliClient.EXPECT().
+		GetPullRequestState(gomock.Any(), "feature-b").
+		Return(client.PullRequestStateOpen, nil)
+
+	mockGitHelper.EXPECT().GetChildren("main").Return([]string{"feature-b"})
+	mockGitHelper.EXPECT().RestackBranches([]string{"feature-b"}).Return(1, nil)
+
+	mockRunner.EXPECT().Git("checkout", "feature-b").Return(nil)
+	mockGitHelper.EXPECT().EndOperation().Return(nil)
+
+	cmd := stack.NewSyncCommand(mockRunner, mockGitHelper, mockCliClient).Command()
+	cmd.SetContext(testCommandContext())
+
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
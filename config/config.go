@@ -45,8 +45,14 @@ type PublicConfigStruct struct {
 
 // LocalConfigStruct represents repository-local configuration (.gtconfig.yaml).
 type LocalConfigStruct struct {
+	// Trunk is the branch stacks grow from; detected from origin/HEAD when unset.
+	Trunk       string                `yaml:"trunk,omitempty"`
 	Protected   []string              `yaml:"protected,omitempty"`
 	MergeMethod constants.MergeMethod `yaml:"merge_method,omitempty"`
+	// BranchPattern names branches created from a commit message, e.g. "{user}/{slug}".
+	BranchPattern string `yaml:"branch_pattern,omitempty"`
+	// ShareMetadata pushes and fetches parent links through refs/gt/meta/<user>.
+	ShareMetadata bool `yaml:"share_metadata,omitempty"`
 }
 
 // DefaultConfigManager implements ConfigManager interface.
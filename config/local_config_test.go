@@ -52,3 +52,6 @@ func (s stubRunner) GitOutput(args ...string) (string, error) {
 	}
 	return "", nil
 }
+func (s stubRunner) GitOutputWithStatus(args ...string) (string, error) {
+	return "", nil
+}
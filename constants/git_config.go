@@ -5,4 +5,29 @@ const (
 	GitConfigPendingPrefix = "gt.pending."
 	GitConfigBranchPrefix  = "gt.branch."
 	GitConfigParentSuffix  = ".parent"
+
+	GitConfigParentRevisionSuffix = ".parentRevision"
+	GitConfigLastChildSuffix      = ".lastChild"
+
+	GitConfigPendingQueue = GitConfigPendingPrefix + "queue"
+	GitConfigPendingChain = GitConfigPendingPrefix + "chain"
+
+	// GitConfigPendingOperation holds the ID of the operation whose rebase is
+	// paused, so `gt abort` only rolls back rebases gt started.
+	GitConfigPendingOperation = GitConfigPendingPrefix + "operation"
+
+	// GitConfigMetadataSynced records the shared metadata commit last pushed
+	// or fetched, the base for merging remote changes with local ones.
+	GitConfigMetadataSynced = "gt.metadata.synced"
+)
+
+// GitMetadataRefPrefix namespaces the shared stack metadata, one ref per user.
+const GitMetadataRefPrefix = "refs/gt/meta/"
+
+// Paths, relative to the git directory, where gt keeps operation state.
+const (
+	GitStateDir            = "gt"
+	GitPendingSnapshotFile = GitStateDir + "/pending.json"
+	GitOplogDir            = GitStateDir + "/oplog"
+	GitRestackWorktreeDir  = GitStateDir + "/worktree"
 )
@@ -12,4 +12,5 @@ const (
 	KeyR     = "r"
 	KeyY     = "y"
 	KeyN     = "n"
+	KeyX     = "x"
 )
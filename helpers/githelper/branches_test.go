@@ -1,10 +1,13 @@
 package githelper
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/config"
 	"github.com/pavlovic265/265-gt/mocks"
 )
 
@@ -146,116 +149,610 @@ func TestRebaseBranch(t *testing.T) {
 	branch := "feature1"
 	parent := "main"
 
-	// checkout
+	expectGraph(mockRunner, "gt.branch."+branch+".parent "+parent, branch, parent)
+
+	gomock.InOrder(
+		// parent tip is no longer part of the branch
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+			Return("newtip", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "newtip", branch).
+			Return("", errors.New("exit status 1")),
+		// recorded fork point is still valid
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get", "gt.branch."+branch+".parentRevision").
+			Return("oldtip", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "oldtip", branch).
+			Return("", nil),
+		// new base
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+			Return("newtip", nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.parent", parent).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.child", branch).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("rebase", "--onto", parent, "oldtip", branch).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "--unset", "gt.pending.parent").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "--unset", "gt.pending.child").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.branch."+branch+".parent", parent).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.branch."+branch+".parentRevision", "newtip").
+			Return(nil),
+	)
+
+	err := gitHelper.RebaseBranch(branch, parent)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRebaseBranch_PrefersForkPointAboveParentTip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	branch := "feature2"
+	parent := "main"
+
+	expectGraph(mockRunner, "gt.branch."+branch+".parent "+parent, branch, parent)
+
+	gomock.InOrder(
+		// feature2 was stacked on a branch that moved away; main's tip is
+		// still below it, but so is the old tip of that branch.
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+			Return("maintip", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "maintip", branch).
+			Return("", nil),
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get", "gt.branch."+branch+".parentRevision").
+			Return("movedtip", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "movedtip", branch).
+			Return("", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "maintip", "movedtip").
+			Return("", nil),
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+			Return("maintip", nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.parent", parent).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.child", branch).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("rebase", "--onto", parent, "movedtip", branch).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "--unset", "gt.pending.parent").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "--unset", "gt.pending.child").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.branch."+branch+".parent", parent).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.branch."+branch+".parentRevision", "maintip").
+			Return(nil),
+	)
+
+	err := gitHelper.RebaseBranch(branch, parent)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestRebaseBranch_FallsBackToMergeBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	branch := "feature1"
+	parent := "develop"
+
+	expectGraph(mockRunner, "gt.branch."+branch+".parent main", branch, "main", parent)
+
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+			Return("maintip", nil),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "--is-ancestor", "maintip", branch).
+			Return("", errors.New("exit status 1")),
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get", "gt.branch."+branch+".parentRevision").
+			Return("", errors.New("exit status 1")),
+		mockRunner.EXPECT().
+			GitOutput("merge-base", "main", branch).
+			Return("forkpoint", nil),
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+			Return("developtip", nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.parent", parent).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.child", branch).
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("rebase", "--onto", parent, "forkpoint", branch).
+			Return(errors.New("conflict")),
+		// No operation is pending, so there is nothing to mark as paused.
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--git-path", "gt/pending.json").
+			Return("/tmp/gt-test-missing/pending.json", nil),
+	)
+
+	err := gitHelper.RebaseBranch(branch, parent)
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestRebaseBranch_MissingParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	branch := "feature1"
+	parent := "gone"
+	expectedError := errors.New("unknown revision")
+
+	expectGraph(mockRunner, "gt.branch."+branch+".parent "+parent, branch)
 	mockRunner.EXPECT().
-		Git("checkout", branch).
-		Return(nil).
-		Times(1)
+		GitOutput("rev-parse", "--verify", "--quiet", parent+"^{commit}").
+		Return("", expectedError)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch."+branch+".parentRevision").
+		Return("", expectedError)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", parent, branch).
+		Return("", expectedError).
+		Times(2)
+
+	err := gitHelper.RebaseBranch(branch, parent)
+
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestCountCommits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
 
-	// set pending parent
 	mockRunner.EXPECT().
-		Git("config", "--local", "gt.pending.parent", parent).
-		Return(nil).
+		GitOutput("rev-list", "--count", "main..feature1").
+		Return("3", nil).
 		Times(1)
 
-	// set pending child
+	count, err := gitHelper.CountCommits("main", "feature1")
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 commits, got %d", count)
+	}
+}
+
+func TestGetCommits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
 	mockRunner.EXPECT().
-		Git("config", "--local", "gt.pending.child", branch).
-		Return(nil).
+		GitOutput("log", "--format=%h %s", "main..feature1").
+		Return("abc123 second\ndef456 first", nil).
 		Times(1)
 
-	// rebase
+	commits, err := gitHelper.GetCommits("main", "feature1")
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(commits) != 2 || commits[0] != "abc123 second" || commits[1] != "def456 first" {
+		t.Errorf("Unexpected commits: %v", commits)
+	}
+}
+
+func TestNeedsRestack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
 	mockRunner.EXPECT().
-		Git("rebase", parent).
-		Return(nil).
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("abc123", nil).
 		Times(1)
-
-	// clear pending parent
 	mockRunner.EXPECT().
-		Git("config", "--local", "--unset", "gt.pending.parent").
-		Return(nil).
+		GitOutput("merge-base", "--is-ancestor", "main", "feature1").
+		Return("", errors.New("exit status 1")).
 		Times(1)
 
-	// clear pending child
+	if !gitHelper.NeedsRestack("feature1", "main") {
+		t.Error("Expected branch to need restack")
+	}
+}
+
+func TestNeedsRestack_MissingParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
 	mockRunner.EXPECT().
-		Git("config", "--local", "--unset", "gt.pending.child").
-		Return(nil).
+		GitOutput("rev-parse", "--verify", "--quiet", "gone^{commit}").
+		Return("", errors.New("exit status 1")).
 		Times(1)
 
-	// SetParent
+	if gitHelper.NeedsRestack("feature1", "gone") {
+		t.Error("Expected no restack when parent is missing")
+	}
+}
+
+func TestGetTrunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
 	mockRunner.EXPECT().
-		Git("config", "--local", "gt.branch."+branch+".parent", parent).
-		Return(nil).
-		Times(1)
+		GitOutput("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD").
+		Return("", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "master^{commit}").
+		Return("abc123", nil)
 
-	err := gitHelper.RebaseBranch(branch, parent)
+	trunk, err := gitHelper.GetTrunk(context.Background())
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+	if trunk != "master" {
+		t.Errorf("Expected 'master', got '%s'", trunk)
+	}
 }
 
-func TestRebaseBranch_CheckoutError(t *testing.T) {
+func TestGetTrunk_FromOriginHead(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	gitHelper := &GitHelperImpl{runner: mockRunner}
 
-	branch := "feature1"
-	parent := "main"
-	expectedError := errors.New("checkout failed")
-
 	mockRunner.EXPECT().
-		Git("checkout", branch).
-		Return(expectedError).
+		GitOutput("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD").
+		Return("origin/develop", nil).
+		Times(1)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "develop^{commit}").
+		Return("abc123", nil).
 		Times(1)
 
-	err := gitHelper.RebaseBranch(branch, parent)
+	for range 2 {
+		trunk, err := gitHelper.GetTrunk(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if trunk != "develop" {
+			t.Errorf("Expected 'develop', got '%s'", trunk)
+		}
+	}
+}
 
-	if err == nil {
-		t.Error("Expected error, got nil")
+func TestGetTrunk_Configured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	cfg := config.NewConfigContext(nil, &config.LocalConfigStruct{Trunk: "release"})
+	ctx := config.WithConfig(context.Background(), cfg)
+
+	trunk, err := gitHelper.GetTrunk(ctx)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if trunk != "release" {
+		t.Errorf("Expected 'release', got '%s'", trunk)
+	}
+	if !gitHelper.IsProtectedBranch(ctx, "release") {
+		t.Error("Expected configured trunk to be protected")
 	}
 }
 
-func TestRebaseBranch_RebaseError(t *testing.T) {
+func TestSetPendingQueue(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRunner := mocks.NewMockRunner(ctrl)
 	gitHelper := &GitHelperImpl{runner: mockRunner}
 
-	branch := "feature1"
-	parent := "main"
-	expectedError := errors.New("rebase failed")
+	mockRunner.EXPECT().
+		Git("config", "--local", "gt.pending.queue", "feature-b feature-c").
+		Return(nil)
+
+	err := gitHelper.SetPendingQueue([]string{"feature-b", "feature-c"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetPendingQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
 
-	// checkout success
 	mockRunner.EXPECT().
-		Git("checkout", branch).
-		Return(nil).
-		Times(1)
+		GitOutput("config", "--local", "--get", "gt.pending.queue").
+		Return("feature-b feature-c", nil)
+
+	queue, err := gitHelper.GetPendingQueue()
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(queue) != 2 || queue[0] != "feature-b" || queue[1] != "feature-c" {
+		t.Errorf("Expected [feature-b feature-c], got %v", queue)
+	}
+}
+
+func TestRestackBranches_SkipsUntrackedAndClearsQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
 
-	// set pending parent
 	mockRunner.EXPECT().
-		Git("config", "--local", "gt.pending.parent", parent).
-		Return(nil).
-		Times(1)
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\..*\.parent$`).
+		Return("", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname)", "refs/heads/").
+		Return("refs/heads/feature-a", nil)
+
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get", "gt.pending.queue").
+			Return("feature-a", nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "--unset", "gt.pending.queue").
+			Return(nil),
+	)
 
-	// set pending child
+	restacked, err := gitHelper.RestackBranches([]string{"feature-a"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if restacked != 0 {
+		t.Errorf("Expected 0 restacked branches, got %d", restacked)
+	}
+}
+
+func TestRestackChain_SkipsBranchOnParentTip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.feature-a.parent main", "feature-a", "main")
 	mockRunner.EXPECT().
-		Git("config", "--local", "gt.pending.child", branch).
-		Return(nil).
-		Times(1)
+		GitOutput("config", "--local", "--get", "gt.pending.chain").
+		Return("", errors.New("exit status 1")).
+		Times(2)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("abc123", nil).
+		Times(2)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "abc123", "feature-a").
+		Return("", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch.feature-a.parentRevision").
+		Return("", errors.New("exit status 1"))
+
+	restacked, err := gitHelper.RestackChain([]string{"feature-a"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if restacked != 0 {
+		t.Errorf("Expected 0 restacked branches, got %d", restacked)
+	}
+}
+
+// expectGraph expects the two reads that load the branch graph cache.
+func expectGraph(mockRunner *mocks.MockRunner, parents string, branches ...string) {
+	refs := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		refs = append(refs, "refs/heads/"+branch)
+	}
 
-	// rebase fails
 	mockRunner.EXPECT().
-		Git("rebase", parent).
-		Return(expectedError).
-		Times(1)
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\..*\.parent$`).
+		Return(parents, nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname)", "refs/heads/").
+		Return(strings.Join(refs, "\n"), nil)
+}
 
-	err := gitHelper.RebaseBranch(branch, parent)
+func TestGetChildren_LoadsGraphOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	if err == nil {
-		t.Error("Expected error, got nil")
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner,
+		"gt.branch.feature.b.parent main\n"+
+			"gt.branch.feature-a.parent main\n"+
+			"gt.branch.deleted.parent main\n"+
+			"gt.branch.feature-c.parent feature-a",
+		"feature-a", "feature.b", "feature-c", "main")
+
+	children := gitHelper.GetChildren("main")
+	if len(children) != 2 || children[0] != "feature-a" || children[1] != "feature.b" {
+		t.Errorf("Expected [feature-a feature.b], got %v", children)
+	}
+
+	children = gitHelper.GetChildren("feature-a")
+	if len(children) != 1 || children[0] != "feature-c" {
+		t.Errorf("Expected [feature-c], got %v", children)
+	}
+
+	parent, err := gitHelper.GetParent("feature.b")
+	if err != nil || parent != "main" {
+		t.Errorf("Expected parent main, got %q (%v)", parent, err)
+	}
+
+	if _, err := gitHelper.GetParent("main"); err == nil {
+		t.Error("Expected error for untracked branch, got nil")
+	}
+}
+
+func TestGetDescendants_BreadthFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner,
+		"gt.branch.a.parent main\n"+
+			"gt.branch.b.parent main\n"+
+			"gt.branch.c.parent a\n"+
+			"gt.branch.d.parent c",
+		"a", "b", "c", "d", "main")
+
+	descendants := gitHelper.GetDescendants([]string{"a", "b", "a"})
+	if strings.Join(descendants, " ") != "a b c d" {
+		t.Errorf("Expected [a b c d], got %v", descendants)
+	}
+}
+
+func TestPinChildren_OverwritesStaleRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner,
+		"gt.branch.b.parent a\n"+
+			"gt.branch.c.parent a",
+		"a", "b", "c")
+	// b still contains the old tip and gets it whatever was recorded before;
+	// c was rebased elsewhere with plain git and keeps its own revision.
+	mockRunner.EXPECT().GitOutput("merge-base", "--is-ancestor", "oldtip", "b").Return("", nil)
+	mockRunner.EXPECT().Git("config", "--local", "gt.branch.b.parentRevision", "oldtip").Return(nil)
+	mockRunner.EXPECT().GitOutput("merge-base", "--is-ancestor", "oldtip", "c").Return("", errors.New("exit status 1"))
+
+	if err := gitHelper.PinChildren("a", "oldtip"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestSetParentAndDeleteParent_UpdateCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.feature-a.parent main", "feature-a", "main")
+	mockRunner.EXPECT().
+		Git("config", "--local", "gt.branch.feature-b.parent", "feature-a").
+		Return(nil)
+	mockRunner.EXPECT().
+		Git("config", "--local", "--unset", "gt.branch.feature-a.parent").
+		Return(nil)
+	mockRunner.EXPECT().
+		Git("config", "--local", "--unset", "gt.branch.feature-a.parentRevision").
+		Return(nil)
+
+	if children := gitHelper.GetChildren("feature-a"); len(children) != 0 {
+		t.Errorf("Expected no children, got %v", children)
+	}
+
+	if err := gitHelper.SetParent("feature-a", "feature-b"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if children := gitHelper.GetChildren("feature-a"); len(children) != 1 || children[0] != "feature-b" {
+		t.Errorf("Expected [feature-b], got %v", children)
+	}
+
+	if err := gitHelper.DeleteParent("feature-a"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if children := gitHelper.GetChildren("main"); len(children) != 0 {
+		t.Errorf("Expected no children of main, got %v", children)
+	}
+}
+
+func TestMoveBranchMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner,
+		"gt.branch.old.parent main\ngt.branch.child.parent old",
+		"child", "main", "new")
+	mockRunner.EXPECT().
+		Git("config", "--local", "--rename-section", "gt.branch.old", "gt.branch.new").
+		Return(nil)
+	mockRunner.EXPECT().
+		Git("config", "--local", "gt.branch.child.parent", "new").
+		Return(nil)
+
+	if err := gitHelper.MoveBranchMetadata("old", "new"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
 	}
 }
@@ -3,12 +3,15 @@ package githelper
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/pavlovic265/265-gt/config"
 	"github.com/pavlovic265/265-gt/constants"
+	"github.com/pavlovic265/265-gt/oplog"
 	"github.com/pavlovic265/265-gt/runner"
 	"github.com/pavlovic265/265-gt/utils/log"
 )
@@ -17,7 +20,17 @@ type GitHelper interface {
 	SetParent(parent string, child string) error
 	GetParent(branch string) (string, error)
 	DeleteParent(branch string) error
+	SetParentRevision(branch string, revision string) error
+	GetParentRevision(branch string) (string, error)
+	SetLastChild(parent string, child string) error
+	GetLastChild(parent string) (string, error)
+	GetRevision(ref string) (string, error)
+	GetMergeBase(a string, b string) (string, error)
 	GetChildren(branch string) []string
+	GetDescendants(branches []string) []string
+	PinChildren(branch string, oldTip string) error
+	GetParents() map[string]string
+	MoveBranchMetadata(oldName string, newName string) error
 	GetCurrentBranch() (string, error)
 	GetBranches() ([]string, error)
 	GetRemoteBranches() ([]string, error)
@@ -25,18 +38,52 @@ type GitHelper interface {
 	SetPending(branchType constants.Branch, branch string) error
 	GetPending(branchType constants.Branch) (string, error)
 	DeletePending(branchType constants.Branch) error
+	SetPendingQueue(queue []string) error
+	GetPendingQueue() ([]string, error)
+	DeletePendingQueue() error
+	RestackBranches(queue []string) (int, error)
+	RestackChain(chain []string) (int, error)
+	GetPendingChain() ([]string, error)
+	SimulateRebase(branch string, parent string, onto string) (string, []string, error)
+	TakeSnapshot(operation string) (*oplog.Snapshot, error)
+	RestoreSnapshot(snapshot *oplog.Snapshot) error
+	UndoOperation(snapshot *oplog.Snapshot, force bool) error
+	BeginOperation(operation string) error
+	GetPendingOperation() (*oplog.Snapshot, error)
+	PauseOperation() error
+	IsPausedOperation(operation *oplog.Snapshot) bool
+	SettleOperation() error
+	EndOperation() error
+	DiscardOperation() error
+	GetOperationLog() ([]*oplog.Snapshot, error)
+	DeleteOperation(id string) error
 	IsGitRepository() error
 	GetGitRoot() (string, error)
 	EnsureGitRepository() error
 	IsProtectedBranch(ctx context.Context, branch string) bool
+	GetTrunk(ctx context.Context) (string, error)
+	DetectTrunk() (string, error)
+	PushMetadata(ctx context.Context) error
+	PullMetadata(ctx context.Context) (int, error)
 	RelinkParentChildren(parent string, branchChildren []string) error
 	IsRebaseInProgress() bool
 	GetRemoteURL(remoteName string) (string, error)
 	ValidateBranchName(name string) error
+	CountCommits(base string, head string) (int, error)
+	GetCommits(base string, head string) ([]string, error)
+	NeedsRestack(branch string, parent string) bool
 }
 
 type GitHelperImpl struct {
 	runner runner.Runner
+	graph  *branchGraph
+	// detectedTrunk caches DetectTrunk, which IsProtectedBranch calls often.
+	detectedTrunk string
+	// worktree is the temporary worktree of the restack in progress.
+	worktree string
+	// noMergeBase is set once `git merge-tree` rejects --merge-base (git
+	// before 2.40), after which SimulateRebase predictions are approximate.
+	noMergeBase bool
 }
 
 func NewGitHelper(runner runner.Runner) GitHelper {
@@ -45,17 +92,68 @@ func NewGitHelper(runner runner.Runner) GitHelper {
 
 func (gh *GitHelperImpl) SetParent(parent string, child string) error {
 	key := constants.GitConfigBranchPrefix + child + constants.GitConfigParentSuffix
-	return gh.runner.Git("config", "--local", key, parent)
+	if err := gh.runner.Git("config", "--local", key, parent); err != nil {
+		return err
+	}
+
+	if gh.graph != nil {
+		gh.graph.parents[child] = parent
+		gh.graph.branches[child] = true
+	}
+	return nil
 }
 
 func (gh *GitHelperImpl) GetParent(branch string) (string, error) {
-	key := constants.GitConfigBranchPrefix + branch + constants.GitConfigParentSuffix
-	return gh.runner.GitOutput("config", "--local", "--get", key)
+	parent, ok := gh.loadGraph().parents[branch]
+	if !ok {
+		return "", fmt.Errorf("no parent recorded for branch %s", branch)
+	}
+	return parent, nil
 }
 
 func (gh *GitHelperImpl) DeleteParent(branch string) error {
 	key := constants.GitConfigBranchPrefix + branch + constants.GitConfigParentSuffix
-	return gh.runner.Git("config", "--local", "--unset", key)
+	if err := gh.runner.Git("config", "--local", "--unset", key); err != nil {
+		return err
+	}
+
+	if gh.graph != nil {
+		delete(gh.graph.parents, branch)
+	}
+
+	revisionKey := constants.GitConfigBranchPrefix + branch + constants.GitConfigParentRevisionSuffix
+	_ = gh.runner.Git("config", "--local", "--unset", revisionKey)
+	return nil
+}
+
+func (gh *GitHelperImpl) SetParentRevision(branch string, revision string) error {
+	key := constants.GitConfigBranchPrefix + branch + constants.GitConfigParentRevisionSuffix
+	return gh.runner.Git("config", "--local", key, revision)
+}
+
+func (gh *GitHelperImpl) GetParentRevision(branch string) (string, error) {
+	key := constants.GitConfigBranchPrefix + branch + constants.GitConfigParentRevisionSuffix
+	return gh.runner.GitOutput("config", "--local", "--get", key)
+}
+
+// SetLastChild remembers which child `gt up` last moved to from parent, so
+// navigation through a fork does not prompt every time.
+func (gh *GitHelperImpl) SetLastChild(parent string, child string) error {
+	key := constants.GitConfigBranchPrefix + parent + constants.GitConfigLastChildSuffix
+	return gh.runner.Git("config", "--local", key, child)
+}
+
+func (gh *GitHelperImpl) GetLastChild(parent string) (string, error) {
+	key := constants.GitConfigBranchPrefix + parent + constants.GitConfigLastChildSuffix
+	return gh.runner.GitOutput("config", "--local", "--get", key)
+}
+
+func (gh *GitHelperImpl) GetRevision(ref string) (string, error) {
+	return gh.runner.GitOutput("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+}
+
+func (gh *GitHelperImpl) GetMergeBase(a string, b string) (string, error) {
+	return gh.runner.GitOutput("merge-base", a, b)
 }
 
 func (gh *GitHelperImpl) SetPending(branchType constants.Branch, branch string) error {
@@ -70,23 +168,110 @@ func (gh *GitHelperImpl) DeletePending(branchType constants.Branch) error {
 	return gh.runner.Git("config", "--local", "--unset", constants.GitConfigPendingPrefix+branchType.String())
 }
 
-func (gh *GitHelperImpl) GetChildren(branch string) []string {
-	branches, err := gh.GetBranches()
+func (gh *GitHelperImpl) SetPendingQueue(queue []string) error {
+	if len(queue) == 0 {
+		return gh.DeletePendingQueue()
+	}
+	return gh.runner.Git("config", "--local", constants.GitConfigPendingQueue, strings.Join(queue, " "))
+}
+
+func (gh *GitHelperImpl) GetPendingQueue() ([]string, error) {
+	output, err := gh.runner.GitOutput("config", "--local", "--get", constants.GitConfigPendingQueue)
 	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
+func (gh *GitHelperImpl) DeletePendingQueue() error {
+	if _, err := gh.GetPendingQueue(); err != nil {
 		return nil
 	}
+	return gh.runner.Git("config", "--local", "--unset", constants.GitConfigPendingQueue)
+}
 
-	var children []string
-	for _, b := range branches {
-		parent, err := gh.GetParent(b)
-		if err != nil {
+func (gh *GitHelperImpl) GetPendingChain() ([]string, error) {
+	output, err := gh.runner.GitOutput("config", "--local", "--get", constants.GitConfigPendingChain)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
+func (gh *GitHelperImpl) setPendingChain(chain []string) error {
+	if len(chain) == 0 {
+		return gh.deletePendingChain()
+	}
+	return gh.runner.Git("config", "--local", constants.GitConfigPendingChain, strings.Join(chain, " "))
+}
+
+func (gh *GitHelperImpl) deletePendingChain() error {
+	if _, err := gh.GetPendingChain(); err != nil {
+		return nil
+	}
+	return gh.runner.Git("config", "--local", "--unset", constants.GitConfigPendingChain)
+}
+
+func (gh *GitHelperImpl) GetChildren(branch string) []string {
+	return gh.loadGraph().children(branch)
+}
+
+// GetDescendants returns branches and every branch stacked on them,
+// breadth-first, each once.
+func (gh *GitHelperImpl) GetDescendants(branches []string) []string {
+	var order []string
+	seen := make(map[string]bool)
+	queue := slices.Clone(branches)
+	for len(queue) > 0 {
+		branch := queue[0]
+		queue = queue[1:]
+		if seen[branch] {
+			continue
+		}
+		seen[branch] = true
+		order = append(order, branch)
+		queue = append(queue, gh.GetChildren(branch)...)
+	}
+	return order
+}
+
+// PinChildren records oldTip, the tip of branch before it was rewritten, as
+// the parent revision of every child that contains it. It replaces any older
+// revision, which may predate a plain-git rebase of the child and would replay
+// commits the child no longer has.
+func (gh *GitHelperImpl) PinChildren(branch string, oldTip string) error {
+	for _, child := range gh.GetChildren(branch) {
+		if !gh.isAncestor(oldTip, child) {
 			continue
 		}
-		if parent == branch {
-			children = append(children, b)
+		if err := gh.SetParentRevision(child, oldTip); err != nil {
+			return fmt.Errorf("failed to record parent revision of %s: %w", child, err)
+		}
+	}
+	return nil
+}
+
+func (gh *GitHelperImpl) GetParents() map[string]string {
+	return maps.Clone(gh.loadGraph().parents)
+}
+
+func (gh *GitHelperImpl) MoveBranchMetadata(oldName string, newName string) error {
+	_, tracked := gh.loadGraph().parents[oldName]
+	children := gh.GetChildren(oldName)
+
+	oldSection := constants.GitConfigBranchPrefix + oldName
+	newSection := constants.GitConfigBranchPrefix + newName
+	if err := gh.runner.Git("config", "--local", "--rename-section", oldSection, newSection); err != nil && tracked {
+		return err
+	}
+	gh.graph = nil
+
+	for _, child := range children {
+		if err := gh.SetParent(newName, child); err != nil {
+			return err
 		}
 	}
-	return children
+	return nil
 }
 
 func (gh *GitHelperImpl) ValidateBranchName(name string) error {
@@ -143,14 +328,21 @@ func (gh *GitHelperImpl) GetRemoteBranches() ([]string, error) {
 }
 
 func (gh *GitHelperImpl) RebaseBranch(branch string, parent string) error {
-	if err := gh.runner.Git("checkout", branch); err != nil {
-		return fmt.Errorf("failed to checkout branch: %w", err)
+	oldBase, err := gh.getRebaseBase(branch, parent)
+	if err != nil {
+		return fmt.Errorf("failed to find fork point of '%s': %w", branch, err)
+	}
+
+	newBase, err := gh.GetRevision(parent)
+	if err != nil {
+		return fmt.Errorf("failed to resolve parent branch '%s': %w", parent, err)
 	}
 
 	_ = gh.SetPending(constants.ParentBranch, parent)
 	_ = gh.SetPending(constants.ChildBranch, branch)
 
-	if err := gh.runner.Git("rebase", parent); err != nil {
+	if err := gh.runner.Git("rebase", "--onto", parent, oldBase, branch); err != nil {
+		_ = gh.PauseOperation()
 		log.Warning("Rebase paused due to conflicts. Resolve them, then run `gt cont` or abort.")
 		return fmt.Errorf("rebase paused: %w", err)
 	}
@@ -161,12 +353,161 @@ func (gh *GitHelperImpl) RebaseBranch(branch string, parent string) error {
 	if err := gh.SetParent(parent, branch); err != nil {
 		return fmt.Errorf("failed to set parent branch relationship: %w", err)
 	}
+	if err := gh.SetParentRevision(branch, newBase); err != nil {
+		return fmt.Errorf("failed to record parent revision: %w", err)
+	}
 
 	log.Successf("Branch '%s' rebased onto '%s' successfully", branch, parent)
 
 	return nil
 }
 
+// getRebaseBase finds the commit the branch's own history starts after, so that
+// `rebase --onto` replays only the branch's commits even when its old parent was
+// amended or squash-merged.
+func (gh *GitHelperImpl) getRebaseBase(branch string, parent string) (string, error) {
+	oldParent, err := gh.GetParent(branch)
+	if err != nil || oldParent == "" {
+		oldParent = parent
+	}
+
+	tip, err := gh.GetRevision(oldParent)
+	tipIsBase := err == nil && gh.isAncestor(tip, branch)
+
+	// The recorded fork point also wins when it sits above the parent's tip,
+	// e.g. after the branch it was stacked on moved out from under it.
+	if revision, err := gh.GetParentRevision(branch); err == nil && revision != "" && gh.isAncestor(revision, branch) {
+		if !tipIsBase || (revision != tip && gh.isAncestor(tip, revision)) {
+			return revision, nil
+		}
+	}
+
+	if tipIsBase {
+		return tip, nil
+	}
+
+	if base, err := gh.GetMergeBase(oldParent, branch); err == nil {
+		return base, nil
+	}
+	return gh.GetMergeBase(parent, branch)
+}
+
+func (gh *GitHelperImpl) isAncestor(ancestor string, ref string) bool {
+	_, err := gh.runner.GitOutput("merge-base", "--is-ancestor", ancestor, ref)
+	return err == nil
+}
+
+// RestackBranches rebases each queued branch onto its recorded parent, followed by
+// its descendants, breadth-first. Before every rebase the branches still left to do
+// are saved as the pending queue, so `gt cont` can resume after a conflict. Linear
+// stretches of the stack are moved with one `rebase --update-refs`.
+func (gh *GitHelperImpl) RestackBranches(queue []string) (int, error) {
+	defer gh.removeRestackWorktree()
+
+	restacked := 0
+	for len(queue) > 0 {
+		branch := queue[0]
+		queue = queue[1:]
+
+		parent, err := gh.GetParent(branch)
+		if err != nil || parent == "" || parent == branch {
+			continue
+		}
+
+		if !gh.isOnParentTip(branch, parent) {
+			if stack := gh.linearStack(branch); len(stack) > 1 && gh.restackLinear(stack, parent) == nil {
+				restacked += len(stack)
+				top := stack[len(stack)-1]
+				for _, child := range gh.GetChildren(top) {
+					if child != top {
+						queue = append(queue, child)
+					}
+				}
+				continue
+			}
+		}
+
+		for _, child := range gh.GetChildren(branch) {
+			if child != branch {
+				queue = append(queue, child)
+			}
+		}
+
+		if err := gh.SetPendingQueue(queue); err != nil {
+			return restacked, fmt.Errorf("failed to save pending restack queue: %w", err)
+		}
+
+		rebased, err := gh.restackBranch(branch, parent)
+		if err != nil {
+			return restacked, err
+		}
+		if rebased {
+			restacked++
+		}
+	}
+
+	return restacked, gh.DeletePendingQueue()
+}
+
+// RestackChain rebases exactly the given branches, in order, without descending
+// into their children. The branches still left to do are saved as the pending
+// chain, so `gt cont` resumes the same chain after a conflict.
+func (gh *GitHelperImpl) RestackChain(chain []string) (int, error) {
+	defer gh.removeRestackWorktree()
+
+	restacked := 0
+	for i, branch := range chain {
+		parent, err := gh.GetParent(branch)
+		if err != nil || parent == "" || parent == branch {
+			continue
+		}
+
+		if err := gh.setPendingChain(chain[i+1:]); err != nil {
+			return restacked, fmt.Errorf("failed to save pending restack chain: %w", err)
+		}
+
+		rebased, err := gh.restackBranch(branch, parent)
+		if err != nil {
+			return restacked, err
+		}
+		if rebased {
+			restacked++
+		}
+	}
+
+	return restacked, gh.deletePendingChain()
+}
+
+// restackBranch rebases branch onto parent unless its own commits already start
+// at the parent's tip. The rebase runs in the restack worktree and only falls back
+// to the user's checkout when that fails, e.g. on conflicts or when the branch is
+// the one checked out.
+func (gh *GitHelperImpl) restackBranch(branch string, parent string) (bool, error) {
+	tip, err := gh.GetRevision(parent)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve parent branch '%s': %w", parent, err)
+	}
+	base, err := gh.getRebaseBase(branch, parent)
+	if err != nil {
+		return true, gh.RebaseBranch(branch, parent)
+	}
+	if base == tip {
+		return false, nil
+	}
+	if gh.checkedOutBranches()[branch] {
+		return true, gh.RebaseBranch(branch, parent)
+	}
+
+	if err := gh.rebaseInWorktree(parent, base, branch, false); err != nil {
+		return true, gh.RebaseBranch(branch, parent)
+	}
+	if err := gh.SetParentRevision(branch, tip); err != nil {
+		return true, fmt.Errorf("failed to record parent revision: %w", err)
+	}
+	log.Successf("Branch '%s' rebased onto '%s' successfully", branch, parent)
+	return true, nil
+}
+
 func (gh *GitHelperImpl) RelinkParentChildren(parent string, branchChildren []string) error {
 	if parent == "" {
 		return nil
@@ -181,6 +522,36 @@ func (gh *GitHelperImpl) RelinkParentChildren(parent string, branchChildren []st
 	return nil
 }
 
+func (gh *GitHelperImpl) CountCommits(base string, head string) (int, error) {
+	output, err := gh.runner.GitOutput("rev-list", "--count", base+".."+head)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(output)
+}
+
+func (gh *GitHelperImpl) GetCommits(base string, head string) ([]string, error) {
+	output, err := gh.runner.GitOutput("log", "--format=%h %s", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+func (gh *GitHelperImpl) NeedsRestack(branch string, parent string) bool {
+	if _, err := gh.GetRevision(parent); err != nil {
+		return false
+	}
+	return !gh.isAncestor(parent, branch)
+}
+
 func (gh *GitHelperImpl) IsRebaseInProgress() bool {
 	path, err := gh.runner.GitOutput("rev-parse", "--git-path", "rebase-merge")
 	if err == nil {
@@ -242,9 +613,46 @@ func (gh *GitHelperImpl) IsProtectedBranch(ctx context.Context, branch string) b
 		return true
 	}
 
+	if trunk, err := gh.GetTrunk(ctx); err == nil && trunk == branch {
+		return true
+	}
+
 	cfg, ok := config.GetConfig(ctx)
 	if !ok || cfg.Local == nil {
 		return false
 	}
 	return slices.Contains(cfg.Local.Protected, branch)
 }
+
+// GetTrunk returns the trunk configured in .gtconfig.yaml, or the detected one.
+func (gh *GitHelperImpl) GetTrunk(ctx context.Context) (string, error) {
+	if cfg, ok := config.GetConfig(ctx); ok && cfg.Local != nil && cfg.Local.Trunk != "" {
+		return cfg.Local.Trunk, nil
+	}
+	return gh.DetectTrunk()
+}
+
+// DetectTrunk picks the branch origin/HEAD points at, falling back to the first
+// existing default branch.
+func (gh *GitHelperImpl) DetectTrunk() (string, error) {
+	if gh.detectedTrunk != "" {
+		return gh.detectedTrunk, nil
+	}
+
+	if ref, err := gh.runner.GitOutput("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		branch := strings.TrimPrefix(ref, "origin/")
+		if _, err := gh.GetRevision(branch); err == nil {
+			gh.detectedTrunk = branch
+			return branch, nil
+		}
+	}
+
+	for _, branch := range defaultProtectedBranches {
+		if _, err := gh.GetRevision(branch); err == nil {
+			gh.detectedTrunk = branch
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no trunk branch found (looked for origin/HEAD, %s)",
+		strings.Join(defaultProtectedBranches, ", "))
+}
@@ -0,0 +1,61 @@
+package githelper
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/constants"
+)
+
+// branchGraph caches the recorded parent links and the local branches for the
+// life of the command, so walking a stack does not spawn a git process per branch.
+type branchGraph struct {
+	parents  map[string]string
+	branches map[string]bool
+}
+
+func (gh *GitHelperImpl) loadGraph() *branchGraph {
+	if gh.graph != nil {
+		return gh.graph
+	}
+
+	graph := &branchGraph{
+		parents:  make(map[string]string),
+		branches: make(map[string]bool),
+	}
+
+	// git exits non-zero when no key matches, which means nothing is tracked.
+	pattern := `^gt\.branch\..*\.parent$`
+	if output, err := gh.runner.GitOutput("config", "--local", "--get-regexp", pattern); err == nil {
+		for _, line := range strings.Split(output, "\n") {
+			key, parent, _ := strings.Cut(strings.TrimSpace(line), " ")
+			branch := strings.TrimPrefix(key, constants.GitConfigBranchPrefix)
+			branch = strings.TrimSuffix(branch, constants.GitConfigParentSuffix)
+			if branch != "" && branch != key {
+				graph.parents[branch] = parent
+			}
+		}
+	}
+
+	if output, err := gh.runner.GitOutput("for-each-ref", "--format=%(refname)", "refs/heads/"); err == nil {
+		for _, line := range strings.Split(output, "\n") {
+			if ref := strings.TrimSpace(line); ref != "" {
+				graph.branches[strings.TrimPrefix(ref, "refs/heads/")] = true
+			}
+		}
+	}
+
+	gh.graph = graph
+	return graph
+}
+
+func (g *branchGraph) children(branch string) []string {
+	var children []string
+	for child, parent := range g.parents {
+		if parent == branch && g.branches[child] {
+			children = append(children, child)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
@@ -0,0 +1,269 @@
+package githelper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/config"
+	"github.com/pavlovic265/265-gt/constants"
+)
+
+// metadataSubject heads every shared metadata commit; the body holds one
+// "branch parent [parentRevision]" line per tracked branch.
+const metadataSubject = "gt stack metadata"
+
+type metadataEntry struct {
+	parent   string
+	revision string
+}
+
+// PushMetadata publishes the parent links of local branches to
+// refs/gt/meta/<user> on origin. It does nothing unless share_metadata is set.
+func (gh *GitHelperImpl) PushMetadata(ctx context.Context) error {
+	ref, err := metadataRef(ctx)
+	if err != nil || ref == "" {
+		return err
+	}
+
+	commit, err := gh.writeMetadata(ref)
+	if err != nil {
+		return err
+	}
+
+	if err := gh.runner.Git("push", "-q", "--force", "origin", ref+":"+ref); err != nil {
+		return fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	return gh.runner.Git("config", "--local", constants.GitConfigMetadataSynced, commit)
+}
+
+// PullMetadata fetches every user's shared metadata from origin and merges it
+// into the local gt.branch.* keys, returning how many branches changed. The
+// user's own metadata updates links that were not changed locally since the
+// last sync; teammates' metadata only fills in branches that are not tracked.
+// Entries for branches without a local ref stay in refs/gt/meta/* and are
+// applied by the first sync after the branch is fetched.
+func (gh *GitHelperImpl) PullMetadata(ctx context.Context) (int, error) {
+	ref, err := metadataRef(ctx)
+	if err != nil || ref == "" {
+		return 0, err
+	}
+
+	prefix := constants.GitMetadataRefPrefix
+	if err := gh.runner.Git("fetch", "-q", "origin", "+"+prefix+"*:"+prefix+"*"); err != nil {
+		return 0, fmt.Errorf("failed to fetch shared metadata: %w", err)
+	}
+
+	output, err := gh.runner.GitOutput("for-each-ref", "--format=%(refname)", prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list shared metadata: %w", err)
+	}
+	var others []string
+	for _, line := range strings.Split(output, "\n") {
+		if other := strings.TrimSpace(line); other != "" && other != ref {
+			others = append(others, other)
+		}
+	}
+
+	graph := gh.loadGraph()
+	local := gh.recordedMetadata()
+	updated := 0
+
+	own, _ := gh.GetRevision(ref)
+	if own != "" {
+		synced, _ := gh.runner.GitOutput("config", "--local", "--get", constants.GitConfigMetadataSynced)
+		base := gh.readMetadata(synced)
+		entries := gh.readMetadata(own)
+
+		for _, branch := range sortedBranches(entries) {
+			if !graph.branches[branch] {
+				continue
+			}
+			entry := entries[branch]
+			current, tracked := local[branch]
+			if tracked && current != entry && current != base[branch] {
+				continue
+			}
+			changed, err := gh.applyMetadata(branch, current, entry)
+			if err != nil {
+				return updated, err
+			}
+			if changed {
+				local[branch] = entry
+				updated++
+			}
+		}
+
+		if err := gh.runner.Git("config", "--local", constants.GitConfigMetadataSynced, own); err != nil {
+			return updated, err
+		}
+	}
+
+	for _, other := range others {
+		entries := gh.readMetadata(other)
+		for _, branch := range sortedBranches(entries) {
+			if _, tracked := local[branch]; tracked || !graph.branches[branch] {
+				continue
+			}
+			if _, err := gh.applyMetadata(branch, metadataEntry{}, entries[branch]); err != nil {
+				return updated, err
+			}
+			local[branch] = entries[branch]
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// metadataRef returns the active user's metadata ref, or "" when sharing is off.
+func metadataRef(ctx context.Context) (string, error) {
+	cfg, ok := config.GetConfig(ctx)
+	if !ok || cfg.Local == nil || !cfg.Local.ShareMetadata {
+		return "", nil
+	}
+	if cfg.Global == nil || cfg.Global.ActiveAccount == nil || cfg.Global.ActiveAccount.User == "" {
+		return "", fmt.Errorf("share_metadata needs an active account")
+	}
+	return constants.GitMetadataRefPrefix + cfg.Global.ActiveAccount.User, nil
+}
+
+// writeMetadata records the local parent links as a commit on ref, reusing
+// the current commit when nothing changed.
+func (gh *GitHelperImpl) writeMetadata(ref string) (string, error) {
+	graph := gh.loadGraph()
+	entries := make(map[string]metadataEntry)
+	for branch, entry := range gh.recordedMetadata() {
+		if graph.branches[branch] {
+			entries[branch] = entry
+		}
+	}
+	message := formatMetadata(entries)
+
+	previous, _ := gh.GetRevision(ref)
+	if previous != "" {
+		if current, err := gh.runner.GitOutput("log", "-1", "--format=%B", previous); err == nil && current == message {
+			return previous, nil
+		}
+	}
+
+	tree, err := gh.runner.GitOutput("hash-object", "-t", "tree", "-w", os.DevNull)
+	if err != nil {
+		return "", fmt.Errorf("failed to write empty tree: %w", err)
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if previous != "" {
+		args = append(args, "-p", previous)
+	}
+	commit, err := gh.runner.GitOutput(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to write shared metadata: %w", err)
+	}
+
+	if err := gh.runner.Git("update-ref", ref, commit); err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+	return commit, nil
+}
+
+func (gh *GitHelperImpl) readMetadata(revision string) map[string]metadataEntry {
+	if revision == "" {
+		return map[string]metadataEntry{}
+	}
+	message, err := gh.runner.GitOutput("log", "-1", "--format=%B", revision)
+	if err != nil {
+		return map[string]metadataEntry{}
+	}
+	return parseMetadata(message)
+}
+
+// recordedMetadata returns the parent links kept in git config. git lowercases
+// the variable name, so parentRevision comes back as parentrevision.
+func (gh *GitHelperImpl) recordedMetadata() map[string]metadataEntry {
+	parentSuffix := strings.ToLower(constants.GitConfigParentSuffix)
+	revisionSuffix := strings.ToLower(constants.GitConfigParentRevisionSuffix)
+
+	entries := make(map[string]metadataEntry)
+	for key, value := range gh.readBranchConfig() {
+		name := strings.TrimPrefix(key, constants.GitConfigBranchPrefix)
+		if branch, ok := strings.CutSuffix(name, parentSuffix); ok {
+			entry := entries[branch]
+			entry.parent = value
+			entries[branch] = entry
+		} else if branch, ok := strings.CutSuffix(name, revisionSuffix); ok {
+			entry := entries[branch]
+			entry.revision = value
+			entries[branch] = entry
+		}
+	}
+
+	for branch, entry := range entries {
+		if entry.parent == "" {
+			delete(entries, branch)
+		}
+	}
+	return entries
+}
+
+func (gh *GitHelperImpl) applyMetadata(branch string, current, entry metadataEntry) (bool, error) {
+	if current == entry {
+		return false, nil
+	}
+	if current.parent != entry.parent {
+		if err := gh.SetParent(entry.parent, branch); err != nil {
+			return false, err
+		}
+	}
+	if entry.revision != "" && current.revision != entry.revision {
+		if err := gh.SetParentRevision(branch, entry.revision); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func formatMetadata(entries map[string]metadataEntry) string {
+	lines := []string{metadataSubject}
+	if len(entries) > 0 {
+		lines = append(lines, "")
+	}
+	for _, branch := range sortedBranches(entries) {
+		line := branch + " " + entries[branch].parent
+		if revision := entries[branch].revision; revision != "" {
+			line += " " + revision
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseMetadata(message string) map[string]metadataEntry {
+	entries := make(map[string]metadataEntry)
+	lines := strings.Split(message, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != metadataSubject {
+		return entries
+	}
+
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 2:
+			entries[fields[0]] = metadataEntry{parent: fields[1]}
+		case 3:
+			entries[fields[0]] = metadataEntry{parent: fields[1], revision: fields[2]}
+		}
+	}
+	return entries
+}
+
+func sortedBranches(entries map[string]metadataEntry) []string {
+	branches := make([]string, 0, len(entries))
+	for branch := range entries {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	return branches
+}
@@ -0,0 +1,139 @@
+package githelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/config"
+	"github.com/pavlovic265/265-gt/mocks"
+)
+
+func sharingContext() context.Context {
+	cfg := config.NewConfigContext(&config.GlobalConfigStruct{
+		ActiveAccount: &config.Account{User: "alice"},
+	}, &config.LocalConfigStruct{ShareMetadata: true})
+	return config.WithConfig(context.Background(), cfg)
+}
+
+func TestFormatAndParseMetadata(t *testing.T) {
+	entries := map[string]metadataEntry{
+		"feature-b": {parent: "feature-a", revision: "abc123"},
+		"feature-a": {parent: "main"},
+	}
+
+	message := formatMetadata(entries)
+	expected := "gt stack metadata\n\nfeature-a main\nfeature-b feature-a abc123"
+	if message != expected {
+		t.Errorf("Expected %q, got %q", expected, message)
+	}
+
+	parsed := parseMetadata(message)
+	if len(parsed) != 2 || parsed["feature-a"] != entries["feature-a"] || parsed["feature-b"] != entries["feature-b"] {
+		t.Errorf("Expected %v, got %v", entries, parsed)
+	}
+}
+
+func TestParseMetadata_IgnoresForeignMessages(t *testing.T) {
+	if parsed := parseMetadata("Initial commit\n\nfeature-a main"); len(parsed) != 0 {
+		t.Errorf("Expected no entries, got %v", parsed)
+	}
+}
+
+func TestPushMetadata_DisabledByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	if err := gitHelper.PushMetadata(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestPushMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\..*\.parent$`).
+		Return("gt.branch.feature-a.parent main\ngt.branch.gone.parent main", nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname)", "refs/heads/").
+		Return("refs/heads/main\nrefs/heads/feature-a", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+		Return("gt.branch.feature-a.parent main\ngt.branch.feature-a.parentrevision abc123\ngt.branch.gone.parent main", nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "refs/gt/meta/alice^{commit}").
+		Return("", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("hash-object", "-t", "tree", "-w", gomock.Any()).
+		Return("tree1", nil)
+	mockRunner.EXPECT().
+		GitOutput("commit-tree", "tree1", "-m", "gt stack metadata\n\nfeature-a main abc123").
+		Return("commit1", nil)
+	mockRunner.EXPECT().Git("update-ref", "refs/gt/meta/alice", "commit1").Return(nil)
+	mockRunner.EXPECT().Git("push", "-q", "--force", "origin", "refs/gt/meta/alice:refs/gt/meta/alice").Return(nil)
+	mockRunner.EXPECT().Git("config", "--local", "gt.metadata.synced", "commit1").Return(nil)
+
+	if err := gitHelper.PushMetadata(sharingContext()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestPullMetadata_MergesOwnAndTeammates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	mockRunner.EXPECT().Git("fetch", "-q", "origin", "+refs/gt/meta/*:refs/gt/meta/*").Return(nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname)", "refs/gt/meta/").
+		Return("refs/gt/meta/alice\nrefs/gt/meta/bob", nil)
+	expectGraph(mockRunner, "gt.branch.a.parent main\ngt.branch.b.parent a\ngt.branch.d.parent x",
+		"main", "a", "b", "d", "e")
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+		Return("gt.branch.a.parent main\ngt.branch.b.parent a\ngt.branch.d.parent x", nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "refs/gt/meta/alice^{commit}").
+		Return("own1", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.metadata.synced").
+		Return("base1", nil)
+	mockRunner.EXPECT().
+		GitOutput("log", "-1", "--format=%B", "base1").
+		Return("gt stack metadata\n\na main\nb a\nd main", nil)
+	mockRunner.EXPECT().
+		GitOutput("log", "-1", "--format=%B", "own1").
+		Return("gt stack metadata\n\na develop\nb a\nc a\nd main", nil)
+	mockRunner.EXPECT().
+		GitOutput("log", "-1", "--format=%B", "refs/gt/meta/bob").
+		Return("gt stack metadata\n\nb main\ne b 123\nf e", nil)
+
+	// a is unchanged locally since the last sync, so the remote move wins; d
+	// was re-parented locally and keeps its local parent.
+	mockRunner.EXPECT().Git("config", "--local", "gt.branch.a.parent", "develop").Return(nil)
+	mockRunner.EXPECT().Git("config", "--local", "gt.metadata.synced", "own1").Return(nil)
+	// A teammate's metadata only fills in branches that are not tracked; c and
+	// f have no local branch and are left alone.
+	mockRunner.EXPECT().Git("config", "--local", "gt.branch.e.parent", "b").Return(nil)
+	mockRunner.EXPECT().Git("config", "--local", "gt.branch.e.parentRevision", "123").Return(nil)
+
+	updated, err := gitHelper.PullMetadata(sharingContext())
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("Expected 2 updated branches, got %d", updated)
+	}
+}
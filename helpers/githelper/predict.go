@@ -0,0 +1,84 @@
+package githelper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/utils/log"
+)
+
+// SimulateRebase replays branch's own commits onto onto in memory with
+// `git merge-tree`, as restacking it onto parent would, and returns the commit
+// it would end at and the files that would conflict. Nothing is checked out and
+// no ref moves; the result is a dangling commit so branches stacked on it can be
+// simulated in turn. A branch already based on onto is returned unchanged.
+func (gh *GitHelperImpl) SimulateRebase(branch string, parent string, onto string) (string, []string, error) {
+	revision, err := gh.GetRevision(branch)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve branch '%s': %w", branch, err)
+	}
+
+	base, err := gh.getRebaseBase(branch, parent)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find fork point of '%s': %w", branch, err)
+	}
+	if base == onto {
+		return revision, nil, nil
+	}
+
+	tree, conflicts, err := gh.mergeTree(base, onto, revision)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to simulate restack of '%s': %w", branch, err)
+	}
+
+	// The original revision as a second parent keeps it the merge base for
+	// branches stacked on this one when merge-tree has to find it itself.
+	commit, err := gh.runner.GitOutput(
+		"commit-tree", tree, "-p", onto, "-p", revision, "-m", "gt restack --dry-run "+branch)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to record simulated restack of '%s': %w", branch, err)
+	}
+	return commit, conflicts, nil
+}
+
+// mergeTree merges base..head onto onto and returns the resulting tree, which
+// holds conflict markers when the returned file list is not empty.
+func (gh *GitHelperImpl) mergeTree(base string, onto string, head string) (string, []string, error) {
+	args := []string{"merge-tree", "--write-tree", "--name-only", "--no-messages"}
+
+	var output string
+	var err error
+	if !gh.noMergeBase {
+		output, err = gh.runner.GitOutputWithStatus(append(args, "--merge-base="+base, onto, head)...)
+		// Only the option being unknown means git is too old; any other
+		// failure, e.g. a bad revision, is returned as it is below.
+		if err != nil && output == "" && strings.Contains(err.Error(), "unknown option `merge-base") {
+			gh.noMergeBase = true
+			log.Warning("git before 2.40 cannot replay only a branch's own commits; " +
+				"the dry run merges whole branches, so its predictions are approximate")
+		}
+	}
+	if gh.noMergeBase {
+		// Without --merge-base, merge-tree finds the merge base itself.
+		output, err = gh.runner.GitOutputWithStatus(append(args, onto, head)...)
+	}
+	if output == "" {
+		if err == nil {
+			err = fmt.Errorf("git merge-tree returned no tree")
+		}
+		return "", nil, err
+	}
+
+	lines := strings.Split(output, "\n")
+	var conflicts []string
+	for _, line := range lines[1:] {
+		if file := strings.TrimSpace(line); file != "" && !slices.Contains(conflicts, file) {
+			conflicts = append(conflicts, file)
+		}
+	}
+	if err != nil && len(conflicts) == 0 {
+		return "", nil, err
+	}
+	return lines[0], conflicts, nil
+}
@@ -0,0 +1,137 @@
+package githelper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/mocks"
+)
+
+func TestSimulateRebase_ReportsConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.feature-a.parent main", "feature-a", "main")
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "feature-a^{commit}").
+		Return("aaa111", nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("mmm111", nil)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "mmm111", "feature-a").
+		Return("", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch.feature-a.parentRevision").
+		Return("base111", nil)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "base111", "feature-a").
+		Return("", nil)
+	mockRunner.EXPECT().
+		GitOutputWithStatus("merge-tree", "--write-tree", "--name-only", "--no-messages",
+			"--merge-base=base111", "new111", "aaa111").
+		Return("tree111\nlogin.go\nlogin.go", errors.New("exit status 1"))
+	mockRunner.EXPECT().
+		GitOutput("commit-tree", "tree111", "-p", "new111", "-p", "aaa111", "-m", "gt restack --dry-run feature-a").
+		Return("sim111", nil)
+
+	tip, conflicts, err := gitHelper.SimulateRebase("feature-a", "main", "new111")
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if tip != "sim111" {
+		t.Errorf("Expected 'sim111', got '%s'", tip)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "login.go" {
+		t.Errorf("Expected [login.go], got %v", conflicts)
+	}
+}
+
+func TestSimulateRebase_UpToDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.feature-a.parent main", "feature-a", "main")
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "feature-a^{commit}").
+		Return("aaa111", nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("mmm111", nil)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "mmm111", "feature-a").
+		Return("", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch.feature-a.parentRevision").
+		Return("", errors.New("exit status 1"))
+
+	tip, conflicts, err := gitHelper.SimulateRebase("feature-a", "main", "mmm111")
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if tip != "aaa111" {
+		t.Errorf("Expected branch to stay at 'aaa111', got '%s'", tip)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", conflicts)
+	}
+}
+
+func TestMergeTree_FallsBackWithoutMergeBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	mockRunner.EXPECT().
+		GitOutputWithStatus("merge-tree", "--write-tree", "--name-only", "--no-messages",
+			"--merge-base=base111", "new111", "aaa111").
+		Return("", errors.New("unknown option `merge-base'"))
+	mockRunner.EXPECT().
+		GitOutputWithStatus("merge-tree", "--write-tree", "--name-only", "--no-messages", "new111", "aaa111").
+		Return("tree111", nil)
+	// Once --merge-base is known to be unsupported it is not tried again.
+	mockRunner.EXPECT().
+		GitOutputWithStatus("merge-tree", "--write-tree", "--name-only", "--no-messages", "new222", "bbb222").
+		Return("tree222\napi.go", errors.New("exit status 1"))
+
+	tree, conflicts, err := gitHelper.mergeTree("base111", "new111", "aaa111")
+	if err != nil || tree != "tree111" || len(conflicts) != 0 {
+		t.Errorf("Expected tree111 without conflicts, got %q %v %v", tree, conflicts, err)
+	}
+
+	tree, conflicts, err = gitHelper.mergeTree("base222", "new222", "bbb222")
+	if err != nil || tree != "tree222" || len(conflicts) != 1 || conflicts[0] != "api.go" {
+		t.Errorf("Expected tree222 with [api.go], got %q %v %v", tree, conflicts, err)
+	}
+}
+
+func TestMergeTree_ReturnsOtherFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	mockRunner.EXPECT().
+		GitOutputWithStatus("merge-tree", "--write-tree", "--name-only", "--no-messages",
+			"--merge-base=base111", "new111", "missing").
+		Return("", errors.New("merge-tree: missing - not something we can merge"))
+
+	if _, _, err := gitHelper.mergeTree("base111", "new111", "missing"); err == nil {
+		t.Error("Expected the merge-tree error, got nil")
+	}
+	if gitHelper.noMergeBase {
+		t.Error("Expected a bad revision not to be mistaken for an old git")
+	}
+}
@@ -0,0 +1,383 @@
+package githelper
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pavlovic265/265-gt/constants"
+	"github.com/pavlovic265/265-gt/oplog"
+)
+
+func (gh *GitHelperImpl) TakeSnapshot(operation string) (*oplog.Snapshot, error) {
+	branch, err := gh.GetCurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	if branch == "HEAD" {
+		branch = ""
+	}
+
+	refs, err := gh.readBranchRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	return &oplog.Snapshot{
+		Operation: operation,
+		Branch:    branch,
+		CreatedAt: time.Now(),
+		Refs:      refs,
+		Config:    gh.readBranchConfig(),
+	}, nil
+}
+
+func (gh *GitHelperImpl) RestoreSnapshot(snapshot *oplog.Snapshot) error {
+	if err := gh.runner.Git("checkout", "-q", "--detach"); err != nil {
+		return fmt.Errorf("failed to detach HEAD: %w", err)
+	}
+
+	refs, err := gh.readBranchRefs()
+	if err != nil {
+		return err
+	}
+	for branch, revision := range snapshot.Refs {
+		if refs[branch] == revision {
+			continue
+		}
+		if err := gh.runner.Git("update-ref", "refs/heads/"+branch, revision); err != nil {
+			return fmt.Errorf("failed to restore branch %s: %w", branch, err)
+		}
+	}
+
+	// Branches the operation created, e.g. by split, insert or get, go too.
+	var created []string
+	for branch := range refs {
+		if _, ok := snapshot.Refs[branch]; !ok && branch != snapshot.Branch {
+			created = append(created, branch)
+		}
+	}
+	sort.Strings(created)
+	for _, branch := range created {
+		if err := gh.runner.Git("update-ref", "-d", "refs/heads/"+branch); err != nil {
+			return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+		}
+	}
+
+	gh.graph = nil
+	current := gh.readBranchConfig()
+	for key := range current {
+		if _, ok := snapshot.Config[key]; !ok {
+			if err := gh.runner.Git("config", "--local", "--unset", key); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", key, err)
+			}
+		}
+	}
+	for key, value := range snapshot.Config {
+		if current[key] == value {
+			continue
+		}
+		if err := gh.runner.Git("config", "--local", key, value); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", key, err)
+		}
+	}
+
+	if snapshot.Branch == "" {
+		return nil
+	}
+	return gh.runner.Git("checkout", "-q", snapshot.Branch)
+}
+
+// UndoOperation rolls back the refs and metadata the operation changed,
+// including branches it created or deleted, and leaves other branches alone.
+// It refuses while a changed branch has moved since the operation ended,
+// unless force is set. An operation that never ended records no end state, so
+// only force restores it, and then as a whole.
+func (gh *GitHelperImpl) UndoOperation(snapshot *oplog.Snapshot, force bool) error {
+	if snapshot.RefsAfter == nil {
+		if !force {
+			return fmt.Errorf("the %s did not finish, so gt cannot tell its changes from later work; "+
+				"run `gt undo --force` to restore every branch to before it", snapshot.Operation)
+		}
+		return gh.RestoreSnapshot(snapshot)
+	}
+
+	refs, err := gh.readBranchRefs()
+	if err != nil {
+		return err
+	}
+
+	var changed, moved []string
+	for _, branch := range sortedKeys(snapshot.Refs, snapshot.RefsAfter) {
+		if snapshot.Refs[branch] == snapshot.RefsAfter[branch] {
+			continue
+		}
+		changed = append(changed, branch)
+		if refs[branch] != snapshot.RefsAfter[branch] {
+			moved = append(moved, branch)
+		}
+	}
+	if len(moved) > 0 && !force {
+		return fmt.Errorf("%s changed since the %s; run `gt undo --force` to discard those changes",
+			strings.Join(moved, ", "), snapshot.Operation)
+	}
+
+	current, err := gh.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	detached := slices.Contains(changed, current)
+	if detached {
+		if err := gh.runner.Git("checkout", "-q", "--detach"); err != nil {
+			return fmt.Errorf("failed to detach HEAD: %w", err)
+		}
+	}
+
+	for _, branch := range changed {
+		revision, ok := snapshot.Refs[branch]
+		switch {
+		case refs[branch] == revision:
+			continue
+		case !ok:
+			// The operation created the branch, e.g. by split, insert or get.
+			err = gh.runner.Git("update-ref", "-d", "refs/heads/"+branch)
+		default:
+			err = gh.runner.Git("update-ref", "refs/heads/"+branch, revision)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore branch %s: %w", branch, err)
+		}
+	}
+
+	gh.graph = nil
+	config := gh.readBranchConfig()
+	for _, key := range sortedKeys(snapshot.Config, snapshot.ConfigAfter) {
+		value, ok := snapshot.Config[key]
+		if value == snapshot.ConfigAfter[key] || value == config[key] {
+			continue
+		}
+		if ok {
+			err = gh.runner.Git("config", "--local", key, value)
+		} else {
+			err = gh.runner.Git("config", "--local", "--unset", key)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", key, err)
+		}
+	}
+
+	if !detached {
+		return nil
+	}
+	if snapshot.Branch == "" {
+		return nil
+	}
+	return gh.runner.Git("checkout", "-q", snapshot.Branch)
+}
+
+func (gh *GitHelperImpl) BeginOperation(operation string) error {
+	snapshot, err := gh.TakeSnapshot(operation)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot branches: %w", err)
+	}
+
+	dir, err := gh.statePath(constants.GitOplogDir)
+	if err != nil {
+		return err
+	}
+	if err := oplog.Append(dir, snapshot); err != nil {
+		return fmt.Errorf("failed to write operation log: %w", err)
+	}
+
+	path, err := gh.statePath(constants.GitPendingSnapshotFile)
+	if err != nil {
+		return err
+	}
+	return oplog.Write(path, snapshot)
+}
+
+func (gh *GitHelperImpl) GetPendingOperation() (*oplog.Snapshot, error) {
+	path, err := gh.statePath(constants.GitPendingSnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return oplog.Read(path)
+}
+
+// PauseOperation marks the pending operation as paused on a rebase conflict.
+// It records the branch refs in the pending snapshot and the operation's ID in
+// gt.pending.operation.
+func (gh *GitHelperImpl) PauseOperation() error {
+	operation, err := gh.GetPendingOperation()
+	if err != nil || operation == nil {
+		return err
+	}
+
+	refs, err := gh.readBranchRefs()
+	if err != nil {
+		return err
+	}
+	operation.Paused = refs
+
+	path, err := gh.statePath(constants.GitPendingSnapshotFile)
+	if err != nil {
+		return err
+	}
+	if err := oplog.Write(path, operation); err != nil {
+		return fmt.Errorf("failed to record paused operation: %w", err)
+	}
+	return gh.runner.Git("config", "--local", constants.GitConfigPendingOperation, operation.ID)
+}
+
+// IsPausedOperation reports whether operation is the one gt paused on a rebase
+// conflict and no branch moved since, so rolling it back loses no other work.
+func (gh *GitHelperImpl) IsPausedOperation(operation *oplog.Snapshot) bool {
+	if operation == nil || operation.Paused == nil {
+		return false
+	}
+	if id, err := gh.getPendingOperationID(); err != nil || id != operation.ID {
+		return false
+	}
+
+	refs, err := gh.readBranchRefs()
+	return err == nil && maps.Equal(refs, operation.Paused)
+}
+
+// SettleOperation ends the pending operation unless gt paused a rebase on it.
+// Commands defer it so a failure part-way does not leave stale state behind;
+// `gt cont` and `gt abort` pick up a paused operation.
+func (gh *GitHelperImpl) SettleOperation() error {
+	operation, err := gh.GetPendingOperation()
+	if err != nil || operation == nil {
+		return err
+	}
+	if id, err := gh.getPendingOperationID(); err == nil && id == operation.ID && gh.IsRebaseInProgress() {
+		return nil
+	}
+	return gh.EndOperation()
+}
+
+// EndOperation records the branch refs and metadata in the operation's log
+// entry, so `gt undo` can tell its changes from later work, and clears it.
+func (gh *GitHelperImpl) EndOperation() error {
+	operation, err := gh.GetPendingOperation()
+	if err != nil {
+		return err
+	}
+	if operation != nil && operation.ID != "" {
+		refs, err := gh.readBranchRefs()
+		if err != nil {
+			return err
+		}
+		operation.Paused = nil
+		operation.RefsAfter = refs
+		operation.ConfigAfter = gh.readBranchConfig()
+
+		dir, err := gh.statePath(constants.GitOplogDir)
+		if err != nil {
+			return err
+		}
+		if err := oplog.Update(dir, operation); err != nil {
+			return fmt.Errorf("failed to write operation log: %w", err)
+		}
+	}
+
+	return gh.DiscardOperation()
+}
+
+// DiscardOperation clears the pending operation without recording how it
+// ended, for operations abandoned in an unknown state.
+func (gh *GitHelperImpl) DiscardOperation() error {
+	if err := gh.DeletePendingQueue(); err != nil {
+		return err
+	}
+	if err := gh.deletePendingChain(); err != nil {
+		return err
+	}
+	if _, err := gh.getPendingOperationID(); err == nil {
+		if err := gh.runner.Git("config", "--local", "--unset", constants.GitConfigPendingOperation); err != nil {
+			return err
+		}
+	}
+
+	path, err := gh.statePath(constants.GitPendingSnapshotFile)
+	if err != nil {
+		return err
+	}
+	return oplog.Remove(path)
+}
+
+func (gh *GitHelperImpl) GetOperationLog() ([]*oplog.Snapshot, error) {
+	dir, err := gh.statePath(constants.GitOplogDir)
+	if err != nil {
+		return nil, err
+	}
+	return oplog.List(dir)
+}
+
+func (gh *GitHelperImpl) DeleteOperation(id string) error {
+	dir, err := gh.statePath(constants.GitOplogDir)
+	if err != nil {
+		return err
+	}
+	return oplog.Delete(dir, id)
+}
+
+// sortedKeys returns the keys of both maps, sorted.
+func sortedKeys(a, b map[string]string) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (gh *GitHelperImpl) getPendingOperationID() (string, error) {
+	return gh.runner.GitOutput("config", "--local", "--get", constants.GitConfigPendingOperation)
+}
+
+func (gh *GitHelperImpl) statePath(name string) (string, error) {
+	return gh.runner.GitOutput("rev-parse", "--git-path", name)
+}
+
+func (gh *GitHelperImpl) readBranchRefs() (map[string]string, error) {
+	output, err := gh.runner.GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	refs := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		ref, revision, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		refs[strings.TrimPrefix(ref, "refs/heads/")] = revision
+	}
+	return refs, nil
+}
+
+// readBranchConfig returns every gt.branch.* key. git exits non-zero when no key
+// matches, which is treated as no metadata.
+func (gh *GitHelperImpl) readBranchConfig() map[string]string {
+	values := make(map[string]string)
+
+	output, err := gh.runner.GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`)
+	if err != nil {
+		return values
+	}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if key != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,263 @@
+package githelper
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/mocks"
+	"github.com/pavlovic265/265-gt/oplog"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--abbrev-ref", "HEAD").
+		Return("feature-a", nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+		Return("refs/heads/feature-a abc123\nrefs/heads/main def456", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+		Return("gt.branch.feature-a.parent main\ngt.branch.feature-a.parentrevision def456", nil)
+
+	snapshot, err := gitHelper.TakeSnapshot("restack")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if snapshot.Operation != "restack" || snapshot.Branch != "feature-a" {
+		t.Errorf("Unexpected snapshot header: %+v", snapshot)
+	}
+	if snapshot.Refs["feature-a"] != "abc123" || snapshot.Refs["main"] != "def456" {
+		t.Errorf("Unexpected refs: %v", snapshot.Refs)
+	}
+	if snapshot.Config["gt.branch.feature-a.parent"] != "main" {
+		t.Errorf("Unexpected config: %v", snapshot.Config)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	snapshot := &oplog.Snapshot{
+		Operation: "restack",
+		Branch:    "feature-a",
+		Refs:      map[string]string{"feature-a": "abc123", "feature-b": "old456"},
+		Config:    map[string]string{"gt.branch.feature-b.parent": "feature-a"},
+	}
+
+	gomock.InOrder(
+		mockRunner.EXPECT().Git("checkout", "-q", "--detach").Return(nil),
+		mockRunner.EXPECT().
+			GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+			Return("refs/heads/feature-a abc123\nrefs/heads/feature-b new789\nrefs/heads/feature-c fed321", nil),
+		mockRunner.EXPECT().Git("update-ref", "refs/heads/feature-b", "old456").Return(nil),
+		mockRunner.EXPECT().Git("update-ref", "-d", "refs/heads/feature-c").Return(nil),
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+			Return("gt.branch.feature-b.parent main\ngt.branch.feature-c.parent feature-b", nil),
+		mockRunner.EXPECT().Git("config", "--local", "--unset", "gt.branch.feature-c.parent").Return(nil),
+		mockRunner.EXPECT().Git("config", "--local", "gt.branch.feature-b.parent", "feature-a").Return(nil),
+		mockRunner.EXPECT().Git("checkout", "-q", "feature-a").Return(nil),
+	)
+
+	if err := gitHelper.RestoreSnapshot(snapshot); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestPauseOperation_OwnsRebaseUntilBranchesMove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	path := filepath.Join(t.TempDir(), "pending.json")
+	if err := oplog.Write(path, &oplog.Snapshot{ID: "op1", Operation: "restack"}); err != nil {
+		t.Fatal(err)
+	}
+	mockRunner.EXPECT().GitOutput("rev-parse", "--git-path", "gt/pending.json").Return(path, nil).AnyTimes()
+
+	refs := "refs/heads/a abc123\nrefs/heads/main def456"
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+			Return(refs, nil),
+		mockRunner.EXPECT().Git("config", "--local", "gt.pending.operation", "op1").Return(nil),
+	)
+	if err := gitHelper.PauseOperation(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	operation, err := gitHelper.GetPendingOperation()
+	if err != nil || operation.Paused["a"] != "abc123" {
+		t.Fatalf("Expected paused refs to be recorded, got %+v (%v)", operation, err)
+	}
+
+	mockRunner.EXPECT().GitOutput("config", "--local", "--get", "gt.pending.operation").Return("op1", nil).Times(2)
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+			Return(refs, nil),
+		// A branch created with plain git since the pause.
+		mockRunner.EXPECT().
+			GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+			Return(refs+"\nrefs/heads/later 789abc", nil),
+	)
+	if !gitHelper.IsPausedOperation(operation) {
+		t.Error("Expected the operation to own the paused rebase")
+	}
+	if gitHelper.IsPausedOperation(operation) {
+		t.Error("Expected a moved branch to disown the paused rebase")
+	}
+}
+
+func TestIsPausedOperation_NotPausedByGt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	operation := &oplog.Snapshot{ID: "op1", Operation: "sync", Paused: map[string]string{"main": "abc123"}}
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.pending.operation").
+		Return("", errors.New("exit status 1"))
+
+	if gitHelper.IsPausedOperation(operation) {
+		t.Error("Expected a rebase without gt.pending.operation not to be owned")
+	}
+	if gitHelper.IsPausedOperation(&oplog.Snapshot{ID: "op2", Operation: "sync"}) {
+		t.Error("Expected an operation that never paused not to be owned")
+	}
+}
+
+func TestUndoOperation_RestoresOnlyWhatTheOperationChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	// The split rewrote a and created a-part; mywork was made with plain git
+	// afterwards and must survive the undo.
+	snapshot := &oplog.Snapshot{
+		Operation:   "split",
+		Branch:      "a",
+		Refs:        map[string]string{"main": "m1", "a": "a1"},
+		RefsAfter:   map[string]string{"main": "m1", "a": "a2", "a-part": "p1"},
+		Config:      map[string]string{"gt.branch.a.parent": "main"},
+		ConfigAfter: map[string]string{"gt.branch.a.parent": "a-part", "gt.branch.a-part.parent": "main"},
+	}
+
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+			Return("refs/heads/main m1\nrefs/heads/a a2\nrefs/heads/a-part p1\nrefs/heads/mywork w1", nil),
+		mockRunner.EXPECT().GitOutput("rev-parse", "--abbrev-ref", "HEAD").Return("mywork", nil),
+		mockRunner.EXPECT().Git("update-ref", "refs/heads/a", "a1").Return(nil),
+		mockRunner.EXPECT().Git("update-ref", "-d", "refs/heads/a-part").Return(nil),
+		mockRunner.EXPECT().
+			GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+			Return("gt.branch.a.parent a-part\ngt.branch.a-part.parent main\ngt.branch.mywork.parent a", nil),
+		mockRunner.EXPECT().Git("config", "--local", "--unset", "gt.branch.a-part.parent").Return(nil),
+		mockRunner.EXPECT().Git("config", "--local", "gt.branch.a.parent", "main").Return(nil),
+	)
+
+	if err := gitHelper.UndoOperation(snapshot, false); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestUndoOperation_RefusesWhenBranchesMoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	snapshot := &oplog.Snapshot{
+		Operation: "restack",
+		Branch:    "a",
+		Refs:      map[string]string{"main": "m1", "a": "a1"},
+		RefsAfter: map[string]string{"main": "m1", "a": "a2"},
+	}
+
+	// a got a commit with plain git after the restack.
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+		Return("refs/heads/main m1\nrefs/heads/a a3", nil)
+
+	err := gitHelper.UndoOperation(snapshot, false)
+	if err == nil || !strings.Contains(err.Error(), "a changed since the restack") {
+		t.Errorf("Expected a moved branch error, got %v", err)
+	}
+}
+
+func TestUndoOperation_UnfinishedNeedsForce(t *testing.T) {
+	gitHelper := &GitHelperImpl{}
+
+	snapshot := &oplog.Snapshot{Operation: "sync", Refs: map[string]string{"main": "m1"}}
+
+	if err := gitHelper.UndoOperation(snapshot, false); err == nil {
+		t.Error("Expected an operation without an end state to need --force")
+	}
+}
+
+func TestEndOperation_RecordsEndState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	gitDir := t.TempDir()
+	dir := filepath.Join(gitDir, "oplog")
+	path := filepath.Join(gitDir, "pending.json")
+	operation := &oplog.Snapshot{Operation: "restack", Refs: map[string]string{"a": "a1"}}
+	if err := oplog.Append(dir, operation); err != nil {
+		t.Fatal(err)
+	}
+	if err := oplog.Write(path, operation); err != nil {
+		t.Fatal(err)
+	}
+
+	mockRunner.EXPECT().GitOutput("rev-parse", "--git-path", "gt/pending.json").Return(path, nil).AnyTimes()
+	mockRunner.EXPECT().GitOutput("rev-parse", "--git-path", "gt/oplog").Return(dir, nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+		Return("refs/heads/a a2", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get-regexp", `^gt\.branch\.`).
+		Return("gt.branch.a.parent main", nil)
+	// No rebase queue, chain or paused operation is pending.
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", gomock.Any()).
+		Return("", errors.New("exit status 1")).
+		AnyTimes()
+
+	if err := gitHelper.EndOperation(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	operations, err := oplog.List(dir)
+	if err != nil || len(operations) != 1 {
+		t.Fatalf("Expected one logged operation, got %v (%v)", operations, err)
+	}
+	if operations[0].RefsAfter["a"] != "a2" || operations[0].ConfigAfter["gt.branch.a.parent"] != "main" {
+		t.Errorf("Expected the end state to be recorded, got %+v", operations[0])
+	}
+}
@@ -0,0 +1,180 @@
+package githelper
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pavlovic265/265-gt/constants"
+	"github.com/pavlovic265/265-gt/utils/log"
+)
+
+// restackWorktree returns the temporary worktree restacks rebase in, so the
+// user's checkout and files stay untouched. It is created on first use.
+func (gh *GitHelperImpl) restackWorktree() (string, error) {
+	if gh.worktree != "" {
+		return gh.worktree, nil
+	}
+
+	path, err := gh.statePath(constants.GitRestackWorktreeDir)
+	if err != nil {
+		return "", err
+	}
+
+	// A restack that was killed may have left one behind.
+	_, _ = gh.runner.GitOutput("worktree", "remove", "--force", path)
+	_ = os.RemoveAll(path)
+
+	if _, err := gh.runner.GitOutput("worktree", "add", "-q", "-f", "--detach", path); err != nil {
+		return "", fmt.Errorf("failed to create restack worktree: %w", err)
+	}
+	gh.worktree = path
+	return path, nil
+}
+
+func (gh *GitHelperImpl) removeRestackWorktree() {
+	if gh.worktree == "" {
+		return
+	}
+	_, _ = gh.runner.GitOutput("worktree", "remove", "--force", gh.worktree)
+	gh.worktree = ""
+}
+
+// rebaseInWorktree runs `git rebase --onto parent base top` in the restack
+// worktree. On any failure, conflicts included, the rebase is aborted so the
+// caller can redo it in the user's checkout, where `gt cont` expects it.
+func (gh *GitHelperImpl) rebaseInWorktree(parent string, base string, top string, updateRefs bool) error {
+	path, err := gh.restackWorktree()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-C", path, "rebase", "-q"}
+	if updateRefs {
+		args = append(args, "--update-refs")
+	}
+	if _, err = gh.runner.GitOutput(append(args, "--onto", parent, base, top)...); err != nil {
+		_, _ = gh.runner.GitOutput("-C", path, "rebase", "--abort")
+	}
+
+	// Release top so it can be checked out or rebased elsewhere.
+	_, _ = gh.runner.GitOutput("-C", path, "checkout", "-q", "--detach")
+	return err
+}
+
+// restackLinear moves a linear stack onto parent with a single
+// `rebase --update-refs` from its top, then records each branch's new base.
+// It fails without rebasing when another branch points into the stack, which
+// --update-refs would move too, so the caller rebases branch by branch.
+func (gh *GitHelperImpl) restackLinear(stack []string, parent string) error {
+	newBase, err := gh.GetRevision(parent)
+	if err != nil {
+		return err
+	}
+	oldBase, err := gh.getRebaseBase(stack[0], parent)
+	if err != nil {
+		return err
+	}
+
+	top := stack[len(stack)-1]
+	if other, err := gh.branchInRange(oldBase, top, stack); err != nil || other != "" {
+		if err == nil {
+			err = fmt.Errorf("branch %s points into the stack", other)
+		}
+		return err
+	}
+
+	if err := gh.rebaseInWorktree(parent, oldBase, top, true); err != nil {
+		return err
+	}
+
+	for _, branch := range stack {
+		if err := gh.SetParentRevision(branch, newBase); err != nil {
+			return fmt.Errorf("failed to record parent revision: %w", err)
+		}
+		log.Successf("Branch '%s' rebased onto '%s' successfully", branch, parent)
+
+		if newBase, err = gh.GetRevision(branch); err != nil {
+			return err
+		}
+		parent = branch
+	}
+	return nil
+}
+
+// branchInRange returns a local branch outside stack that points at a commit
+// in base..top, e.g. a backup branch, or "" when there is none.
+func (gh *GitHelperImpl) branchInRange(base string, top string, stack []string) (string, error) {
+	output, err := gh.runner.GitOutput("rev-list", base+".."+top)
+	if err != nil {
+		return "", err
+	}
+	commits := make(map[string]bool)
+	for _, commit := range strings.Fields(output) {
+		commits[commit] = true
+	}
+
+	refs, err := gh.readBranchRefs()
+	if err != nil {
+		return "", err
+	}
+	branches := slices.Sorted(maps.Keys(refs))
+	for _, branch := range branches {
+		if commits[refs[branch]] && !slices.Contains(stack, branch) {
+			return branch, nil
+		}
+	}
+	return "", nil
+}
+
+// linearStack returns branch followed by its descendants for as long as each
+// has a single child that already sits on its parent's tip. Rebasing the top of
+// such a stack with --update-refs moves all of it. Branches checked out in a
+// worktree end the stack, since git leaves their refs alone.
+func (gh *GitHelperImpl) linearStack(branch string) []string {
+	checkedOut := gh.checkedOutBranches()
+	if checkedOut[branch] {
+		return nil
+	}
+
+	stack := []string{branch}
+	for {
+		children := gh.GetChildren(branch)
+		if len(children) != 1 {
+			return stack
+		}
+		child := children[0]
+		if checkedOut[child] || slices.Contains(stack, child) || !gh.isOnParentTip(child, branch) {
+			return stack
+		}
+		stack = append(stack, child)
+		branch = child
+	}
+}
+
+// isOnParentTip reports whether branch's own commits start at parent's tip, so
+// rebasing it onto parent would change nothing.
+func (gh *GitHelperImpl) isOnParentTip(branch string, parent string) bool {
+	tip, err := gh.GetRevision(parent)
+	if err != nil {
+		return false
+	}
+	base, err := gh.getRebaseBase(branch, parent)
+	return err == nil && base == tip
+}
+
+func (gh *GitHelperImpl) checkedOutBranches() map[string]bool {
+	branches := make(map[string]bool)
+	output, err := gh.runner.GitOutput("worktree", "list", "--porcelain")
+	if err != nil {
+		return branches
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(line), "branch refs/heads/"); ok {
+			branches[ref] = true
+		}
+	}
+	return branches
+}
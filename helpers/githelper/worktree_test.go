@@ -0,0 +1,179 @@
+package githelper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pavlovic265/265-gt/mocks"
+)
+
+// expectStaleBase makes a's recorded fork point m1 while main has moved to m2.
+func expectStaleBase(mockRunner *mocks.MockRunner) {
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "main^{commit}").
+		Return("m2", nil).AnyTimes()
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "m2", "a").
+		Return("", errors.New("exit status 1")).AnyTimes()
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch.a.parentRevision").
+		Return("m1", nil).AnyTimes()
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "m1", "a").
+		Return("", nil).AnyTimes()
+}
+
+func expectRestackWorktree(mockRunner *mocks.MockRunner) {
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--git-path", "gt/worktree").
+		Return("/tmp/gt-test-missing/worktree", nil)
+	mockRunner.EXPECT().
+		GitOutput("worktree", "remove", "--force", "/tmp/gt-test-missing/worktree").
+		Return("", errors.New("not a working tree")).
+		Times(2)
+	mockRunner.EXPECT().
+		GitOutput("worktree", "add", "-q", "-f", "--detach", "/tmp/gt-test-missing/worktree").
+		Return("", nil)
+	mockRunner.EXPECT().
+		GitOutput("-C", "/tmp/gt-test-missing/worktree", "checkout", "-q", "--detach").
+		Return("", nil)
+}
+
+func TestRestackBranches_MovesLinearStackWithUpdateRefs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.a.parent main\ngt.branch.b.parent a", "a", "b", "main")
+	expectStaleBase(mockRunner)
+
+	// b already sits on a's tip, so the two move together
+	mockRunner.EXPECT().
+		GitOutput("worktree", "list", "--porcelain").
+		Return("worktree /repo\nHEAD m2\nbranch refs/heads/main", nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "a^{commit}").
+		Return("a1", nil).
+		Times(2)
+	mockRunner.EXPECT().
+		GitOutput("merge-base", "--is-ancestor", "a1", "b").
+		Return("", nil).AnyTimes()
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.branch.b.parentRevision").
+		Return("a1", nil)
+
+	mockRunner.EXPECT().
+		GitOutput("rev-list", "m1..b").
+		Return("b1\na1", nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+		Return("refs/heads/a a1\nrefs/heads/b b1\nrefs/heads/main m2", nil)
+
+	expectRestackWorktree(mockRunner)
+	mockRunner.EXPECT().
+		GitOutput("-C", "/tmp/gt-test-missing/worktree", "rebase", "-q", "--update-refs", "--onto", "main", "m1", "b").
+		Return("", nil)
+
+	mockRunner.EXPECT().
+		Git("config", "--local", "gt.branch.a.parentRevision", "m2").
+		Return(nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "a^{commit}").
+		Return("a2", nil)
+	mockRunner.EXPECT().
+		Git("config", "--local", "gt.branch.b.parentRevision", "a2").
+		Return(nil)
+	mockRunner.EXPECT().
+		GitOutput("rev-parse", "--verify", "--quiet", "b^{commit}").
+		Return("b2", nil)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.pending.queue").
+		Return("", errors.New("exit status 1"))
+
+	restacked, err := gitHelper.RestackBranches([]string{"a"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if restacked != 2 {
+		t.Errorf("Expected 2 restacked branches, got %d", restacked)
+	}
+}
+
+func TestRestackLinear_RefusesWhenAnotherBranchPointsIntoStack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.a.parent main\ngt.branch.b.parent a", "a", "a-backup", "b", "main")
+	expectStaleBase(mockRunner)
+
+	// --update-refs would silently move a-backup along with a.
+	mockRunner.EXPECT().
+		GitOutput("rev-list", "m1..b").
+		Return("b1\na1", nil)
+	mockRunner.EXPECT().
+		GitOutput("for-each-ref", "--format=%(refname) %(objectname)", "refs/heads/").
+		Return("refs/heads/a a1\nrefs/heads/a-backup a1\nrefs/heads/b b1\nrefs/heads/main m2", nil)
+
+	err := gitHelper.restackLinear([]string{"a", "b"}, "main")
+	if err == nil || !strings.Contains(err.Error(), "a-backup") {
+		t.Errorf("Expected a-backup to prevent the single rebase, got %v", err)
+	}
+}
+
+func TestRestackChain_FallsBackToCheckoutOnConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRunner := mocks.NewMockRunner(ctrl)
+	gitHelper := &GitHelperImpl{runner: mockRunner}
+
+	expectGraph(mockRunner, "gt.branch.a.parent main", "a", "main")
+	expectStaleBase(mockRunner)
+	mockRunner.EXPECT().
+		GitOutput("config", "--local", "--get", "gt.pending.chain").
+		Return("", errors.New("exit status 1"))
+
+	mockRunner.EXPECT().
+		GitOutput("worktree", "list", "--porcelain").
+		Return("worktree /repo\nHEAD m2\nbranch refs/heads/main", nil)
+	expectRestackWorktree(mockRunner)
+	mockRunner.EXPECT().
+		GitOutput("-C", "/tmp/gt-test-missing/worktree", "rebase", "-q", "--onto", "main", "m1", "a").
+		Return("", errors.New("could not apply"))
+	mockRunner.EXPECT().
+		GitOutput("-C", "/tmp/gt-test-missing/worktree", "rebase", "--abort").
+		Return("", nil)
+
+	// redone in the user's checkout so `gt cont` can pick it up
+	gomock.InOrder(
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.parent", "main").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("config", "--local", "gt.pending.child", "a").
+			Return(nil),
+		mockRunner.EXPECT().
+			Git("rebase", "--onto", "main", "m1", "a").
+			Return(errors.New("exit status 1")),
+		mockRunner.EXPECT().
+			GitOutput("rev-parse", "--git-path", "gt/pending.json").
+			Return("/tmp/gt-test-missing/pending.json", nil),
+	)
+
+	restacked, err := gitHelper.RestackChain([]string{"a"})
+
+	if err == nil {
+		t.Error("Expected the paused rebase to be reported")
+	}
+	if restacked != 0 {
+		t.Errorf("Expected 0 restacked branches, got %d", restacked)
+	}
+}
@@ -116,7 +116,7 @@ func NewApp() (*App, error) {
 	}
 
 	basic.RegisterCommands(app.rootCmd, app.run, app.gitHelper)
-	branch.RegisterCommands(app.rootCmd, app.run, app.gitHelper)
+	branch.RegisterCommands(app.rootCmd, app.run, app.gitHelper, app.cliClient)
 	remote.RegisterCommands(app.rootCmd, app.run, app.gitHelper, app.cliClient)
 	utility.RegisterCommands(app.rootCmd, app.run, app.configManager)
 	stack.RegisterCommands(app.rootCmd, app.run, app.gitHelper, app.cliClient)
@@ -124,7 +124,7 @@ func NewApp() (*App, error) {
 	pr.RegisterCommands(app.rootCmd, app.run, app.configManager, app.gitHelper, app.cliClient)
 	auth.RegisterCommands(app.rootCmd, app.configManager, app.cliClient)
 	account.RegisterCommands(app.rootCmd, app.run, app.configManager, app.cliClient)
-	createconfig.RegisterCommands(app.rootCmd, app.run, app.configManager)
+	createconfig.RegisterCommands(app.rootCmd, app.run, app.configManager, app.gitHelper)
 
 	return app, nil
 }
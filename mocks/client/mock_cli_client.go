@@ -77,6 +77,20 @@ func (mr *MockCliClientMockRecorder) AuthStatus(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthStatus", reflect.TypeOf((*MockCliClient)(nil).AuthStatus), ctx)
 }
 
+// ClosePullRequest mocks base method.
+func (m *MockCliClient) ClosePullRequest(ctx context.Context, branch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePullRequest", ctx, branch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePullRequest indicates an expected call of ClosePullRequest.
+func (mr *MockCliClientMockRecorder) ClosePullRequest(ctx, branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePullRequest", reflect.TypeOf((*MockCliClient)(nil).ClosePullRequest), ctx, branch)
+}
+
 // CreatePullRequest mocks base method.
 func (m *MockCliClient) CreatePullRequest(ctx context.Context, args []string) error {
 	m.ctrl.T.Helper()
@@ -91,6 +105,36 @@ func (mr *MockCliClientMockRecorder) CreatePullRequest(ctx, args interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequest", reflect.TypeOf((*MockCliClient)(nil).CreatePullRequest), ctx, args)
 }
 
+// GetPullRequest mocks base method.
+func (m *MockCliClient) GetPullRequest(ctx context.Context, ref string) (*client.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPullRequest", ctx, ref)
+	ret0, _ := ret[0].(*client.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPullRequest indicates an expected call of GetPullRequest.
+func (mr *MockCliClientMockRecorder) GetPullRequest(ctx, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullRequest", reflect.TypeOf((*MockCliClient)(nil).GetPullRequest), ctx, ref)
+}
+
+// GetPullRequestState mocks base method.
+func (m *MockCliClient) GetPullRequestState(ctx context.Context, branch string) (client.PullRequestState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPullRequestState", ctx, branch)
+	ret0, _ := ret[0].(client.PullRequestState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPullRequestState indicates an expected call of GetPullRequestState.
+func (mr *MockCliClientMockRecorder) GetPullRequestState(ctx, branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPullRequestState", reflect.TypeOf((*MockCliClient)(nil).GetPullRequestState), ctx, branch)
+}
+
 // HasOpenPullRequestForBranch mocks base method.
 func (m *MockCliClient) HasOpenPullRequestForBranch(ctx context.Context, branch string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -135,6 +179,20 @@ func (mr *MockCliClientMockRecorder) MergePullRequest(ctx, prNumber interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePullRequest", reflect.TypeOf((*MockCliClient)(nil).MergePullRequest), ctx, prNumber)
 }
 
+// RenameBranch mocks base method.
+func (m *MockCliClient) RenameBranch(ctx context.Context, branch, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameBranch", ctx, branch, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameBranch indicates an expected call of RenameBranch.
+func (mr *MockCliClientMockRecorder) RenameBranch(ctx, branch, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameBranch", reflect.TypeOf((*MockCliClient)(nil).RenameBranch), ctx, branch, newName)
+}
+
 // UpdatePullRequestBaseBranch mocks base method.
 func (m *MockCliClient) UpdatePullRequestBaseBranch(ctx context.Context, branch string) error {
 	m.ctrl.T.Helper()
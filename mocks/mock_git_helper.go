@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	constants "github.com/pavlovic265/265-gt/constants"
+	oplog "github.com/pavlovic265/265-gt/oplog"
 )
 
 // MockGitHelper is a mock of GitHelper interface.
@@ -35,6 +36,49 @@ func (m *MockGitHelper) EXPECT() *MockGitHelperMockRecorder {
 	return m.recorder
 }
 
+// BeginOperation mocks base method.
+func (m *MockGitHelper) BeginOperation(operation string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginOperation", operation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BeginOperation indicates an expected call of BeginOperation.
+func (mr *MockGitHelperMockRecorder) BeginOperation(operation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginOperation", reflect.TypeOf((*MockGitHelper)(nil).BeginOperation), operation)
+}
+
+// CountCommits mocks base method.
+func (m *MockGitHelper) CountCommits(base, head string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCommits", base, head)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCommits indicates an expected call of CountCommits.
+func (mr *MockGitHelperMockRecorder) CountCommits(base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCommits", reflect.TypeOf((*MockGitHelper)(nil).CountCommits), base, head)
+}
+
+// DeleteOperation mocks base method.
+func (m *MockGitHelper) DeleteOperation(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOperation", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOperation indicates an expected call of DeleteOperation.
+func (mr *MockGitHelperMockRecorder) DeleteOperation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOperation", reflect.TypeOf((*MockGitHelper)(nil).DeleteOperation), id)
+}
+
 // DeleteParent mocks base method.
 func (m *MockGitHelper) DeleteParent(branch string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +107,63 @@ func (mr *MockGitHelperMockRecorder) DeletePending(branchType interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePending", reflect.TypeOf((*MockGitHelper)(nil).DeletePending), branchType)
 }
 
+// DeletePendingQueue mocks base method.
+func (m *MockGitHelper) DeletePendingQueue() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingQueue")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingQueue indicates an expected call of DeletePendingQueue.
+func (mr *MockGitHelperMockRecorder) DeletePendingQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingQueue", reflect.TypeOf((*MockGitHelper)(nil).DeletePendingQueue))
+}
+
+// DetectTrunk mocks base method.
+func (m *MockGitHelper) DetectTrunk() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectTrunk")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectTrunk indicates an expected call of DetectTrunk.
+func (mr *MockGitHelperMockRecorder) DetectTrunk() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectTrunk", reflect.TypeOf((*MockGitHelper)(nil).DetectTrunk))
+}
+
+// DiscardOperation mocks base method.
+func (m *MockGitHelper) DiscardOperation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscardOperation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscardOperation indicates an expected call of DiscardOperation.
+func (mr *MockGitHelperMockRecorder) DiscardOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardOperation", reflect.TypeOf((*MockGitHelper)(nil).DiscardOperation))
+}
+
+// EndOperation mocks base method.
+func (m *MockGitHelper) EndOperation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndOperation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EndOperation indicates an expected call of EndOperation.
+func (mr *MockGitHelperMockRecorder) EndOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndOperation", reflect.TypeOf((*MockGitHelper)(nil).EndOperation))
+}
+
 // EnsureGitRepository mocks base method.
 func (m *MockGitHelper) EnsureGitRepository() error {
 	m.ctrl.T.Helper()
@@ -106,6 +207,21 @@ func (mr *MockGitHelperMockRecorder) GetChildren(branch interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockGitHelper)(nil).GetChildren), branch)
 }
 
+// GetCommits mocks base method.
+func (m *MockGitHelper) GetCommits(base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommits", base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommits indicates an expected call of GetCommits.
+func (mr *MockGitHelperMockRecorder) GetCommits(base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommits", reflect.TypeOf((*MockGitHelper)(nil).GetCommits), base, head)
+}
+
 // GetCurrentBranch mocks base method.
 func (m *MockGitHelper) GetCurrentBranch() (string, error) {
 	m.ctrl.T.Helper()
@@ -121,6 +237,20 @@ func (mr *MockGitHelperMockRecorder) GetCurrentBranch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBranch", reflect.TypeOf((*MockGitHelper)(nil).GetCurrentBranch))
 }
 
+// GetDescendants mocks base method.
+func (m *MockGitHelper) GetDescendants(branches []string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescendants", branches)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetDescendants indicates an expected call of GetDescendants.
+func (mr *MockGitHelperMockRecorder) GetDescendants(branches interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescendants", reflect.TypeOf((*MockGitHelper)(nil).GetDescendants), branches)
+}
+
 // GetGitRoot mocks base method.
 func (m *MockGitHelper) GetGitRoot() (string, error) {
 	m.ctrl.T.Helper()
@@ -136,6 +266,51 @@ func (mr *MockGitHelperMockRecorder) GetGitRoot() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGitRoot", reflect.TypeOf((*MockGitHelper)(nil).GetGitRoot))
 }
 
+// GetLastChild mocks base method.
+func (m *MockGitHelper) GetLastChild(parent string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastChild", parent)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastChild indicates an expected call of GetLastChild.
+func (mr *MockGitHelperMockRecorder) GetLastChild(parent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastChild", reflect.TypeOf((*MockGitHelper)(nil).GetLastChild), parent)
+}
+
+// GetMergeBase mocks base method.
+func (m *MockGitHelper) GetMergeBase(a, b string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMergeBase", a, b)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMergeBase indicates an expected call of GetMergeBase.
+func (mr *MockGitHelperMockRecorder) GetMergeBase(a, b interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMergeBase", reflect.TypeOf((*MockGitHelper)(nil).GetMergeBase), a, b)
+}
+
+// GetOperationLog mocks base method.
+func (m *MockGitHelper) GetOperationLog() ([]*oplog.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperationLog")
+	ret0, _ := ret[0].([]*oplog.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperationLog indicates an expected call of GetOperationLog.
+func (mr *MockGitHelperMockRecorder) GetOperationLog() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationLog", reflect.TypeOf((*MockGitHelper)(nil).GetOperationLog))
+}
+
 // GetParent mocks base method.
 func (m *MockGitHelper) GetParent(branch string) (string, error) {
 	m.ctrl.T.Helper()
@@ -151,6 +326,35 @@ func (mr *MockGitHelperMockRecorder) GetParent(branch interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParent", reflect.TypeOf((*MockGitHelper)(nil).GetParent), branch)
 }
 
+// GetParentRevision mocks base method.
+func (m *MockGitHelper) GetParentRevision(branch string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParentRevision", branch)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParentRevision indicates an expected call of GetParentRevision.
+func (mr *MockGitHelperMockRecorder) GetParentRevision(branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentRevision", reflect.TypeOf((*MockGitHelper)(nil).GetParentRevision), branch)
+}
+
+// GetParents mocks base method.
+func (m *MockGitHelper) GetParents() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParents")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// GetParents indicates an expected call of GetParents.
+func (mr *MockGitHelperMockRecorder) GetParents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParents", reflect.TypeOf((*MockGitHelper)(nil).GetParents))
+}
+
 // GetPending mocks base method.
 func (m *MockGitHelper) GetPending(branchType constants.Branch) (string, error) {
 	m.ctrl.T.Helper()
@@ -166,6 +370,51 @@ func (mr *MockGitHelperMockRecorder) GetPending(branchType interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockGitHelper)(nil).GetPending), branchType)
 }
 
+// GetPendingChain mocks base method.
+func (m *MockGitHelper) GetPendingChain() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingChain")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingChain indicates an expected call of GetPendingChain.
+func (mr *MockGitHelperMockRecorder) GetPendingChain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingChain", reflect.TypeOf((*MockGitHelper)(nil).GetPendingChain))
+}
+
+// GetPendingOperation mocks base method.
+func (m *MockGitHelper) GetPendingOperation() (*oplog.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingOperation")
+	ret0, _ := ret[0].(*oplog.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOperation indicates an expected call of GetPendingOperation.
+func (mr *MockGitHelperMockRecorder) GetPendingOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOperation", reflect.TypeOf((*MockGitHelper)(nil).GetPendingOperation))
+}
+
+// GetPendingQueue mocks base method.
+func (m *MockGitHelper) GetPendingQueue() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingQueue")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingQueue indicates an expected call of GetPendingQueue.
+func (mr *MockGitHelperMockRecorder) GetPendingQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingQueue", reflect.TypeOf((*MockGitHelper)(nil).GetPendingQueue))
+}
+
 // GetRemoteBranches mocks base method.
 func (m *MockGitHelper) GetRemoteBranches() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -196,6 +445,36 @@ func (mr *MockGitHelperMockRecorder) GetRemoteURL(remoteName interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteURL", reflect.TypeOf((*MockGitHelper)(nil).GetRemoteURL), remoteName)
 }
 
+// GetRevision mocks base method.
+func (m *MockGitHelper) GetRevision(ref string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ref)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockGitHelperMockRecorder) GetRevision(ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockGitHelper)(nil).GetRevision), ref)
+}
+
+// GetTrunk mocks base method.
+func (m *MockGitHelper) GetTrunk(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrunk", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrunk indicates an expected call of GetTrunk.
+func (mr *MockGitHelperMockRecorder) GetTrunk(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrunk", reflect.TypeOf((*MockGitHelper)(nil).GetTrunk), ctx)
+}
+
 // IsGitRepository mocks base method.
 func (m *MockGitHelper) IsGitRepository() error {
 	m.ctrl.T.Helper()
@@ -210,6 +489,20 @@ func (mr *MockGitHelperMockRecorder) IsGitRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsGitRepository", reflect.TypeOf((*MockGitHelper)(nil).IsGitRepository))
 }
 
+// IsPausedOperation mocks base method.
+func (m *MockGitHelper) IsPausedOperation(operation *oplog.Snapshot) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPausedOperation", operation)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPausedOperation indicates an expected call of IsPausedOperation.
+func (mr *MockGitHelperMockRecorder) IsPausedOperation(operation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPausedOperation", reflect.TypeOf((*MockGitHelper)(nil).IsPausedOperation), operation)
+}
+
 // IsProtectedBranch mocks base method.
 func (m *MockGitHelper) IsProtectedBranch(ctx context.Context, branch string) bool {
 	m.ctrl.T.Helper()
@@ -238,6 +531,91 @@ func (mr *MockGitHelperMockRecorder) IsRebaseInProgress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRebaseInProgress", reflect.TypeOf((*MockGitHelper)(nil).IsRebaseInProgress))
 }
 
+// MoveBranchMetadata mocks base method.
+func (m *MockGitHelper) MoveBranchMetadata(oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveBranchMetadata", oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveBranchMetadata indicates an expected call of MoveBranchMetadata.
+func (mr *MockGitHelperMockRecorder) MoveBranchMetadata(oldName, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBranchMetadata", reflect.TypeOf((*MockGitHelper)(nil).MoveBranchMetadata), oldName, newName)
+}
+
+// NeedsRestack mocks base method.
+func (m *MockGitHelper) NeedsRestack(branch, parent string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRestack", branch, parent)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRestack indicates an expected call of NeedsRestack.
+func (mr *MockGitHelperMockRecorder) NeedsRestack(branch, parent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRestack", reflect.TypeOf((*MockGitHelper)(nil).NeedsRestack), branch, parent)
+}
+
+// PauseOperation mocks base method.
+func (m *MockGitHelper) PauseOperation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseOperation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseOperation indicates an expected call of PauseOperation.
+func (mr *MockGitHelperMockRecorder) PauseOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseOperation", reflect.TypeOf((*MockGitHelper)(nil).PauseOperation))
+}
+
+// PinChildren mocks base method.
+func (m *MockGitHelper) PinChildren(branch, oldTip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinChildren", branch, oldTip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinChildren indicates an expected call of PinChildren.
+func (mr *MockGitHelperMockRecorder) PinChildren(branch, oldTip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinChildren", reflect.TypeOf((*MockGitHelper)(nil).PinChildren), branch, oldTip)
+}
+
+// PullMetadata mocks base method.
+func (m *MockGitHelper) PullMetadata(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullMetadata", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullMetadata indicates an expected call of PullMetadata.
+func (mr *MockGitHelperMockRecorder) PullMetadata(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullMetadata", reflect.TypeOf((*MockGitHelper)(nil).PullMetadata), ctx)
+}
+
+// PushMetadata mocks base method.
+func (m *MockGitHelper) PushMetadata(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushMetadata", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushMetadata indicates an expected call of PushMetadata.
+func (mr *MockGitHelperMockRecorder) PushMetadata(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushMetadata", reflect.TypeOf((*MockGitHelper)(nil).PushMetadata), ctx)
+}
+
 // RebaseBranch mocks base method.
 func (m *MockGitHelper) RebaseBranch(branch, parent string) error {
 	m.ctrl.T.Helper()
@@ -266,6 +644,64 @@ func (mr *MockGitHelperMockRecorder) RelinkParentChildren(parent, branchChildren
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelinkParentChildren", reflect.TypeOf((*MockGitHelper)(nil).RelinkParentChildren), parent, branchChildren)
 }
 
+// RestackBranches mocks base method.
+func (m *MockGitHelper) RestackBranches(queue []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestackBranches", queue)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestackBranches indicates an expected call of RestackBranches.
+func (mr *MockGitHelperMockRecorder) RestackBranches(queue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestackBranches", reflect.TypeOf((*MockGitHelper)(nil).RestackBranches), queue)
+}
+
+// RestackChain mocks base method.
+func (m *MockGitHelper) RestackChain(chain []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestackChain", chain)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestackChain indicates an expected call of RestackChain.
+func (mr *MockGitHelperMockRecorder) RestackChain(chain interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestackChain", reflect.TypeOf((*MockGitHelper)(nil).RestackChain), chain)
+}
+
+// RestoreSnapshot mocks base method.
+func (m *MockGitHelper) RestoreSnapshot(snapshot *oplog.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSnapshot", snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockGitHelperMockRecorder) RestoreSnapshot(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockGitHelper)(nil).RestoreSnapshot), snapshot)
+}
+
+// SetLastChild mocks base method.
+func (m *MockGitHelper) SetLastChild(parent, child string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastChild", parent, child)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastChild indicates an expected call of SetLastChild.
+func (mr *MockGitHelperMockRecorder) SetLastChild(parent, child interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastChild", reflect.TypeOf((*MockGitHelper)(nil).SetLastChild), parent, child)
+}
+
 // SetParent mocks base method.
 func (m *MockGitHelper) SetParent(parent, child string) error {
 	m.ctrl.T.Helper()
@@ -280,6 +716,20 @@ func (mr *MockGitHelperMockRecorder) SetParent(parent, child interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockGitHelper)(nil).SetParent), parent, child)
 }
 
+// SetParentRevision mocks base method.
+func (m *MockGitHelper) SetParentRevision(branch, revision string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParentRevision", branch, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParentRevision indicates an expected call of SetParentRevision.
+func (mr *MockGitHelperMockRecorder) SetParentRevision(branch, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParentRevision", reflect.TypeOf((*MockGitHelper)(nil).SetParentRevision), branch, revision)
+}
+
 // SetPending mocks base method.
 func (m *MockGitHelper) SetPending(branchType constants.Branch, branch string) error {
 	m.ctrl.T.Helper()
@@ -294,6 +744,79 @@ func (mr *MockGitHelperMockRecorder) SetPending(branchType, branch interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPending", reflect.TypeOf((*MockGitHelper)(nil).SetPending), branchType, branch)
 }
 
+// SetPendingQueue mocks base method.
+func (m *MockGitHelper) SetPendingQueue(queue []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingQueue", queue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingQueue indicates an expected call of SetPendingQueue.
+func (mr *MockGitHelperMockRecorder) SetPendingQueue(queue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingQueue", reflect.TypeOf((*MockGitHelper)(nil).SetPendingQueue), queue)
+}
+
+// SettleOperation mocks base method.
+func (m *MockGitHelper) SettleOperation() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleOperation")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SettleOperation indicates an expected call of SettleOperation.
+func (mr *MockGitHelperMockRecorder) SettleOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleOperation", reflect.TypeOf((*MockGitHelper)(nil).SettleOperation))
+}
+
+// SimulateRebase mocks base method.
+func (m *MockGitHelper) SimulateRebase(branch, parent, onto string) (string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateRebase", branch, parent, onto)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SimulateRebase indicates an expected call of SimulateRebase.
+func (mr *MockGitHelperMockRecorder) SimulateRebase(branch, parent, onto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateRebase", reflect.TypeOf((*MockGitHelper)(nil).SimulateRebase), branch, parent, onto)
+}
+
+// TakeSnapshot mocks base method.
+func (m *MockGitHelper) TakeSnapshot(operation string) (*oplog.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshot", operation)
+	ret0, _ := ret[0].(*oplog.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeSnapshot indicates an expected call of TakeSnapshot.
+func (mr *MockGitHelperMockRecorder) TakeSnapshot(operation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshot", reflect.TypeOf((*MockGitHelper)(nil).TakeSnapshot), operation)
+}
+
+// UndoOperation mocks base method.
+func (m *MockGitHelper) UndoOperation(snapshot *oplog.Snapshot, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoOperation", snapshot, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoOperation indicates an expected call of UndoOperation.
+func (mr *MockGitHelperMockRecorder) UndoOperation(snapshot, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoOperation", reflect.TypeOf((*MockGitHelper)(nil).UndoOperation), snapshot, force)
+}
+
 // ValidateBranchName mocks base method.
 func (m *MockGitHelper) ValidateBranchName(name string) error {
 	m.ctrl.T.Helper()
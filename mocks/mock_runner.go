@@ -108,3 +108,22 @@ func (mr *MockRunnerMockRecorder) GitOutput(args ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GitOutput", reflect.TypeOf((*MockRunner)(nil).GitOutput), args...)
 }
+
+// GitOutputWithStatus mocks base method.
+func (m *MockRunner) GitOutputWithStatus(args ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GitOutputWithStatus", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GitOutputWithStatus indicates an expected call of GitOutputWithStatus.
+func (mr *MockRunnerMockRecorder) GitOutputWithStatus(args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GitOutputWithStatus", reflect.TypeOf((*MockRunner)(nil).GitOutputWithStatus), args...)
+}
@@ -0,0 +1,154 @@
+// Package oplog defines the branch snapshots gt records before mutating
+// operations and how they are stored on disk.
+package oplog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxEntries is how many operations the log keeps before dropping the oldest.
+const MaxEntries = 50
+
+// Snapshot captures every local branch ref and the gt branch metadata at the
+// start of an operation, so the operation can be rolled back. RefsAfter and
+// ConfigAfter hold the same state once the operation ended, so an undo only
+// touches what the operation changed.
+type Snapshot struct {
+	ID        string            `json:"id,omitempty"`
+	Operation string            `json:"operation"`
+	Branch    string            `json:"branch"`
+	CreatedAt time.Time         `json:"created_at"`
+	Refs      map[string]string `json:"refs"`
+	Config    map[string]string `json:"config"`
+	// Paused holds the branch refs when the operation paused on a rebase
+	// conflict; `gt abort` only rolls back while they are unchanged.
+	Paused      map[string]string `json:"paused,omitempty"`
+	RefsAfter   map[string]string `json:"refs_after,omitempty"`
+	ConfigAfter map[string]string `json:"config_after,omitempty"`
+}
+
+// Write stores the snapshot as JSON at path, creating parent directories.
+func Write(path string, snapshot *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Read loads a snapshot from path. It returns nil and no error when the file
+// does not exist.
+func Read(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// Remove deletes the snapshot at path, ignoring a missing file.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Append adds the snapshot to the operation log in dir and drops entries
+// beyond MaxEntries. It sets the snapshot ID to the entry's file name.
+func Append(dir string, snapshot *Snapshot) error {
+	snapshot.ID = fmt.Sprintf("%020d-%s", snapshot.CreatedAt.UnixNano(), snapshot.Operation)
+	if err := Write(filepath.Join(dir, snapshot.ID+".json"), snapshot); err != nil {
+		return err
+	}
+
+	ids, err := listIDs(dir)
+	if err != nil {
+		return err
+	}
+	for len(ids) > MaxEntries {
+		if err := Delete(dir, ids[len(ids)-1]); err != nil {
+			return err
+		}
+		ids = ids[:len(ids)-1]
+	}
+	return nil
+}
+
+// Update rewrites the entry of snapshot in the operation log in dir. An entry
+// already dropped from the log is left out.
+func Update(dir string, snapshot *Snapshot) error {
+	path := filepath.Join(dir, snapshot.ID+".json")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return Write(path, snapshot)
+}
+
+// List returns the logged snapshots in dir, newest first.
+func List(dir string) ([]*Snapshot, error) {
+	ids, err := listIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*Snapshot, 0, len(ids))
+	for _, id := range ids {
+		snapshot, err := Read(filepath.Join(dir, id+".json"))
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			snapshot.ID = id
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// Delete removes the entry with the given ID from the operation log in dir.
+func Delete(dir, id string) error {
+	return Remove(filepath.Join(dir, id+".json"))
+}
+
+// listIDs returns entry IDs newest first; IDs start with a zero-padded
+// timestamp, so reverse lexical order is reverse chronological order.
+func listIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
@@ -0,0 +1,102 @@
+package oplog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gt", "pending.json")
+	snapshot := &Snapshot{
+		Operation: "restack",
+		Branch:    "feature-a",
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Refs:      map[string]string{"feature-a": "abc123"},
+		Config:    map[string]string{"gt.branch.feature-a.parent": "main"},
+	}
+
+	require.NoError(t, Write(path, snapshot))
+
+	loaded, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+}
+
+func TestRead_Missing(t *testing.T) {
+	loaded, err := Read(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.NoError(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestRemove_Missing(t *testing.T) {
+	assert.NoError(t, Remove(filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestAppendList(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	require.NoError(t, Append(dir, &Snapshot{Operation: "restack", CreatedAt: base}))
+	require.NoError(t, Append(dir, &Snapshot{Operation: "delete", CreatedAt: base.Add(time.Second)}))
+
+	snapshots, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "delete", snapshots[0].Operation)
+	assert.Equal(t, "restack", snapshots[1].Operation)
+
+	require.NoError(t, Delete(dir, snapshots[0].ID))
+
+	snapshots, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "restack", snapshots[0].Operation)
+}
+
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	snapshot := &Snapshot{Operation: "restack", CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	require.NoError(t, Append(dir, snapshot))
+
+	snapshot.RefsAfter = map[string]string{"feature-a": "def456"}
+	require.NoError(t, Update(dir, snapshot))
+
+	snapshots, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, snapshot.RefsAfter, snapshots[0].RefsAfter)
+
+	// An entry dropped from the log is not written back.
+	require.NoError(t, Delete(dir, snapshot.ID))
+	require.NoError(t, Update(dir, snapshot))
+
+	snapshots, err = List(dir)
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
+
+func TestAppend_DropsOldestEntries(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for i := 0; i < MaxEntries+3; i++ {
+		require.NoError(t, Append(dir, &Snapshot{Operation: "move", CreatedAt: base.Add(time.Duration(i) * time.Second)}))
+	}
+
+	snapshots, err := List(dir)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, MaxEntries)
+	assert.Equal(t, base.Add(time.Duration(MaxEntries+2)*time.Second), snapshots[0].CreatedAt)
+}
+
+func TestList_MissingDir(t *testing.T) {
+	snapshots, err := List(filepath.Join(t.TempDir(), "missing"))
+
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
@@ -15,6 +15,10 @@ type Runner interface {
 	Git(args ...string) error
 	// GitOutput executes a git command and returns the output.
 	GitOutput(args ...string) (string, error)
+	// GitOutputWithStatus executes a git command and returns the output even
+	// when it fails, for commands such as `git merge-tree` that report results
+	// through a non-zero exit.
+	GitOutputWithStatus(args ...string) (string, error)
 	// Exec executes an arbitrary command.
 	Exec(name string, args ...string) error
 	// ExecOutput executes a command and returns the output.
@@ -48,7 +52,21 @@ func (r *runnerImpl) Exec(name string, args ...string) error {
 	return nil
 }
 
+func (r *runnerImpl) GitOutputWithStatus(args ...string) (string, error) {
+	return execOutput("git", args...)
+}
+
 func (r *runnerImpl) ExecOutput(name string, args ...string) (string, error) {
+	output, err := execOutput(name, args...)
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// execOutput runs a command and returns its trimmed stdout together with any
+// error, which carries stderr when there is some.
+func execOutput(name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
 
 	var stdout, stderr bytes.Buffer
@@ -56,11 +74,12 @@ func (r *runnerImpl) ExecOutput(name string, args ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stdout.String())
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
-			return "", fmt.Errorf("%s %s: %s", name, strings.Join(args, " "), errMsg)
+			return output, fmt.Errorf("%s %s: %s", name, strings.Join(args, " "), errMsg)
 		}
-		return "", fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		return output, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
 	}
 
 	return strings.TrimSpace(stdout.String()), nil
@@ -26,3 +26,25 @@ func TestExecOutput_ErrorIncludesStderr(t *testing.T) {
 		t.Fatalf("expected stderr in error, got %v", err)
 	}
 }
+
+func TestExecOutput_ErrorDropsStdout(t *testing.T) {
+	r := NewRunner()
+	out, err := r.ExecOutput("sh", "-c", "echo partial; exit 1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if out != "" {
+		t.Fatalf("expected no output, got %q", out)
+	}
+}
+
+func TestGitOutputWithStatus_ErrorKeepsStdout(t *testing.T) {
+	r := NewRunner()
+	out, err := r.GitOutputWithStatus("-c", "alias.partial=!echo partial; exit 1", "partial")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if out != "partial" {
+		t.Fatalf("expected partial, got %q", out)
+	}
+}
@@ -0,0 +1,135 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavlovic265/265-gt/constants"
+)
+
+// MultiSelectModel lets the user mark any number of choices before confirming.
+type MultiSelectModel struct {
+	Title     string
+	Choices   []string
+	Cursor    int
+	Marked    map[int]bool
+	Confirmed bool
+}
+
+func NewMultiSelectModel(title string, choices []string) MultiSelectModel {
+	return MultiSelectModel{
+		Title:   title,
+		Choices: choices,
+		Marked:  make(map[int]bool),
+	}
+}
+
+func (m MultiSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m MultiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case tea.KeyCtrlC.String(), constants.KeyQ:
+		return m, tea.Quit
+	}
+	if len(m.Choices) == 0 {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case tea.KeyUp.String(), tea.KeyShiftTab.String(), constants.KeyK:
+		if m.Cursor > 0 {
+			m.Cursor--
+		} else {
+			m.Cursor = len(m.Choices) - 1
+		}
+	case tea.KeyDown.String(), tea.KeyTab.String(), constants.KeyJ:
+		if m.Cursor < len(m.Choices)-1 {
+			m.Cursor++
+		} else {
+			m.Cursor = 0
+		}
+	case tea.KeySpace.String(), constants.KeyX:
+		m.Marked[m.Cursor] = !m.Marked[m.Cursor]
+	case tea.KeyEnter.String():
+		m.Confirmed = true
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// MarkedIndexes returns the indexes of the marked choices in ascending order.
+func (m MultiSelectModel) MarkedIndexes() []int {
+	var indexes []int
+	for index, marked := range m.Marked {
+		if marked {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func (m MultiSelectModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(searchLabelStyle.Render(m.Title))
+	content.WriteString("\n\n")
+
+	if len(m.Choices) == 0 {
+		content.WriteString(emptyStateStyle.Render("No items found"))
+		content.WriteString("\n")
+	}
+
+	for i, choice := range m.Choices {
+		cursor := " "
+		style := itemStyle
+		if m.Cursor == i {
+			cursor = cursorStyle.Render(">")
+			style = selectedItemStyle
+		}
+
+		box := "[ ]"
+		if m.Marked[i] {
+			box = "[x]"
+		}
+		content.WriteString(fmt.Sprintf("%s %s\n", cursor, style.Render(box+" "+choice)))
+	}
+
+	content.WriteString("\n")
+	content.WriteString(footerStyle.Render("Press "))
+	content.WriteString(keyStyle.Render("space"))
+	content.WriteString(footerStyle.Render(" to mark, "))
+	content.WriteString(keyStyle.Render("ENTER"))
+	content.WriteString(footerStyle.Render(" to confirm, "))
+	content.WriteString(keyStyle.Render(constants.KeyQ))
+	content.WriteString(footerStyle.Render(" to quit"))
+
+	return content.String()
+}
+
+// MultiSelect displays the choices and returns the indexes the user marked.
+// Returns nil if the user cancelled.
+func MultiSelect(title string, choices []string) ([]int, error) {
+	program := tea.NewProgram(NewMultiSelectModel(title, choices))
+
+	finalModel, err := program.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := finalModel.(MultiSelectModel); ok && m.Confirmed {
+		return m.MarkedIndexes(), nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultiSelectModel_SpaceMarksCursor(t *testing.T) {
+	model := NewMultiSelectModel("Pick", []string{"alpha", "beta", "gamma"})
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	updated, _ = updated.(MultiSelectModel).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	updated, _ = updated.(MultiSelectModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	updated, _ = updated.(MultiSelectModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	next := updated.(MultiSelectModel)
+
+	indexes := next.MarkedIndexes()
+	if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 2 {
+		t.Fatalf("expected [1 2], got %v", indexes)
+	}
+}
+
+func TestMultiSelectModel_SpaceTogglesOff(t *testing.T) {
+	model := NewMultiSelectModel("Pick", []string{"alpha"})
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	updated, _ = updated.(MultiSelectModel).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	next := updated.(MultiSelectModel)
+
+	if len(next.MarkedIndexes()) != 0 {
+		t.Fatalf("expected no marks, got %v", next.MarkedIndexes())
+	}
+}
+
+func TestMultiSelectModel_EnterConfirms(t *testing.T) {
+	model := NewMultiSelectModel("Pick", []string{"alpha"})
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(MultiSelectModel)
+
+	if !next.Confirmed {
+		t.Fatal("expected selection to be confirmed")
+	}
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+}
+
+func TestMultiSelectModel_QuitDoesNotConfirm(t *testing.T) {
+	model := NewMultiSelectModel("Pick", []string{"alpha"})
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	next := updated.(MultiSelectModel)
+
+	if next.Confirmed {
+		t.Fatal("expected selection not to be confirmed")
+	}
+}
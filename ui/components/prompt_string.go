@@ -0,0 +1,85 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavlovic265/265-gt/ui/theme"
+)
+
+// StringPrompt asks for a single line of text. It starts in insert mode;
+// Enter submits, Esc or Ctrl+C cancels.
+type StringPrompt struct {
+	question string
+	input    textinput.Model
+	Value    string
+	Quitting bool
+}
+
+func NewStringPrompt(question string, input textinput.Model) StringPrompt {
+	input.Focus()
+	return StringPrompt{
+		question: question,
+		input:    input,
+	}
+}
+
+func (m StringPrompt) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m StringPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case tea.KeyEnter.String():
+			m.Value = strings.TrimSpace(m.input.Value())
+			return m, tea.Quit
+		case tea.KeyCtrlC.String(), tea.KeyEsc.String():
+			m.Quitting = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m StringPrompt) View() string {
+	if m.Quitting {
+		return fmt.Sprintf("%s %s\n",
+			canceledIconStyle.Render(theme.CrossIcon),
+			canceledStyle.Render("Operation canceled by user"))
+	}
+
+	var content strings.Builder
+	content.WriteString(questionStyle.Render(m.question))
+	content.WriteString("\n")
+	content.WriteString(m.input.View())
+	content.WriteString("\n")
+	content.WriteString(optionsStyle.Render("Press "))
+	content.WriteString(enterKeyStyle.Render("ENTER"))
+	content.WriteString(optionsStyle.Render(" to confirm or "))
+	content.WriteString(quitKeyStyle.Render("ESC"))
+	content.WriteString(optionsStyle.Render(" to cancel"))
+	return content.String()
+}
+
+// PromptString asks the question and returns the entered text.
+// Returns empty string if the user cancelled.
+func PromptString(question string, input textinput.Model) (string, error) {
+	program := tea.NewProgram(NewStringPrompt(question, input))
+
+	finalModel, err := program.Run()
+	if err != nil {
+		return "", err
+	}
+
+	if m, ok := finalModel.(StringPrompt); ok && !m.Quitting {
+		return m.Value, nil
+	}
+
+	return "", nil
+}
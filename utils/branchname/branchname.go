@@ -0,0 +1,57 @@
+// Package branchname generates branch names from commit messages.
+package branchname
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pavlovic265/265-gt/utils/timeutils"
+)
+
+// DefaultPattern is used when the repository does not configure branch_pattern.
+const DefaultPattern = "{slug}"
+
+// maxSlugLength keeps generated names readable in branch lists and PR URLs.
+const maxSlugLength = 50
+
+// FromMessage expands pattern for a commit message. Supported placeholders are
+// {slug} (the message's first line, slugified), {user} and {date} (YYYY-MM-DD).
+func FromMessage(pattern, message, user string, now time.Time) string {
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	replacer := strings.NewReplacer(
+		"{slug}", Slug(subject),
+		"{user}", user,
+		"{date}", now.Format(timeutils.LayoutISO),
+	)
+	return replacer.Replace(pattern)
+}
+
+// Slug lowercases text and joins its letters and digits with dashes, cutting
+// at a word boundary once it grows past maxSlugLength.
+func Slug(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > maxSlugLength && slug != "" {
+			break
+		}
+		slug = next
+	}
+
+	if runes := []rune(slug); len(runes) > maxSlugLength {
+		slug = string(runes[:maxSlugLength])
+	}
+	return slug
+}
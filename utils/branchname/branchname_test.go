@@ -0,0 +1,45 @@
+package branchname
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Add user login":                 "add-user-login",
+		"Fix: crash on empty config!":    "fix-crash-on-empty-config",
+		"  Refactor   API_v2 handlers  ": "refactor-api-v2-handlers",
+		"Ünïcode & symbols":              "ünïcode-symbols",
+		"":                               "",
+	}
+	for input, want := range tests {
+		if got := Slug(input); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSlug_CutsAtWordBoundary(t *testing.T) {
+	got := Slug(strings.Repeat("word ", 20))
+	if len(got) > maxSlugLength {
+		t.Errorf("Slug length = %d, want at most %d", len(got), maxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "word") {
+		t.Errorf("Slug = %q, want it to end on a whole word", got)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	if got := FromMessage("", "Add login\n\nLonger body", "alice", now); got != "add-login" {
+		t.Errorf("FromMessage with default pattern = %q, want %q", got, "add-login")
+	}
+
+	got := FromMessage("{user}/{date}-{slug}", "Add login", "alice", now)
+	if want := "alice/2024-03-09-add-login"; got != want {
+		t.Errorf("FromMessage = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+// Package patch parses zero-context unified diffs (`git diff -U0`) and renders
+// subsets of their hunks as patches `git apply --unidiff-zero` accepts.
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Hunk is one change block. OldStart/OldCount address the preimage; a pure
+// addition has OldCount 0 and OldStart is the line it is inserted after.
+type Hunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Lines    []string
+}
+
+// File is the diff of one path. Only text modifications of an existing file
+// are Modifiable; hunks of new, deleted, renamed or binary files are kept as-is.
+type File struct {
+	Path       string
+	Header     []string
+	Hunks      []Hunk
+	Modifiable bool
+}
+
+// Parse splits `git diff -U0` output into files and hunks.
+func Parse(diff string) ([]File, error) {
+	var files []File
+	var file *File
+	var hunk *Hunk
+
+	flush := func() {
+		if file == nil {
+			return
+		}
+		if hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+			hunk = nil
+		}
+		files = append(files, *file)
+		file = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			file = &File{Header: []string{line}, Modifiable: true}
+		case file == nil:
+			continue
+		case strings.HasPrefix(line, "@@ "):
+			if hunk != nil {
+				file.Hunks = append(file.Hunks, *hunk)
+			}
+			parsed, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			hunk = &parsed
+		case hunk != nil:
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			file.Header = append(file.Header, line)
+			switch {
+			case strings.HasPrefix(line, "+++ b/"):
+				file.Path = strings.TrimPrefix(line, "+++ b/")
+			case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- /dev/null"),
+				strings.HasPrefix(line, "new file mode"), strings.HasPrefix(line, "deleted file mode"),
+				strings.HasPrefix(line, "old mode"), strings.HasPrefix(line, "new mode"),
+				strings.HasPrefix(line, "rename "), strings.HasPrefix(line, "copy "),
+				strings.HasPrefix(line, "Binary files"):
+				file.Modifiable = false
+			}
+		}
+	}
+	flush()
+
+	for i := range files {
+		if files[i].Path == "" {
+			files[i].Modifiable = false
+		}
+	}
+	return files, nil
+}
+
+// parseHunkHeader reads "@@ -a[,b] +c[,d] @@".
+func parseHunkHeader(line string) (Hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "@@" || !strings.HasPrefix(fields[3], "@@") {
+		return Hunk{}, fmt.Errorf("malformed hunk header: %q", line)
+	}
+
+	oldStart, oldCount, err := parseRange(strings.TrimPrefix(fields[1], "-"))
+	if err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	newStart, newCount, err := parseRange(strings.TrimPrefix(fields[2], "+"))
+	if err != nil {
+		return Hunk{}, fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+
+	return Hunk{OldStart: oldStart, OldCount: oldCount, NewStart: newStart, NewCount: newCount}, nil
+}
+
+func parseRange(r string) (int, int, error) {
+	startText, countText, hasCount := strings.Cut(r, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasCount {
+		return start, 1, nil
+	}
+	count, err := strconv.Atoi(countText)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, count, nil
+}
+
+// Render writes hunks of file as a patch against a preimage in which the
+// applied hunks of the same file are already present, renumbering line
+// positions to account for them.
+func Render(file File, hunks []Hunk, applied []Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	sorted := append([]Hunk(nil), hunks...)
+	sort.Slice(sorted, func(i, j int) bool { return position(sorted[i]) < position(sorted[j]) })
+
+	var b strings.Builder
+	for _, line := range file.Header {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	written := 0
+	for _, h := range sorted {
+		oldStart := h.OldStart
+		for _, a := range applied {
+			if position(a) < position(h) {
+				oldStart += a.NewCount - a.OldCount
+			}
+		}
+
+		newStart := oldStart + written
+		switch {
+		case h.OldCount == 0:
+			newStart++
+		case h.NewCount == 0:
+			newStart--
+		}
+		written += h.NewCount - h.OldCount
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, h.OldCount, newStart, h.NewCount)
+		for _, line := range h.Lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// position orders non-overlapping hunks: a pure addition after line n sorts
+// between a change starting at n and one starting at n+1.
+func position(h Hunk) int {
+	if h.OldCount == 0 {
+		return 2*h.OldStart + 1
+	}
+	return 2 * h.OldStart
+}
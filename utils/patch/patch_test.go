@@ -0,0 +1,127 @@
+package patch
+
+import (
+	"testing"
+)
+
+const sampleDiff = `diff --git a/app.go b/app.go
+index 1111111..2222222 100644
+--- a/app.go
++++ b/app.go
+@@ -3 +3 @@ func main() {
+-	fmt.Println("helo")
++	fmt.Println("hello")
+@@ -10,0 +11,2 @@ func run() {
++	one()
++	two()
+@@ -20,2 +22,0 @@ func stop() {
+-	old()
+-	older()
+diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++fresh
+`
+
+func TestParse(t *testing.T) {
+	files, err := Parse(sampleDiff)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+
+	app := files[0]
+	if app.Path != "app.go" || !app.Modifiable {
+		t.Errorf("app.go parsed as path=%q modifiable=%v", app.Path, app.Modifiable)
+	}
+	if len(app.Header) != 4 {
+		t.Errorf("expected 4 header lines, got %d", len(app.Header))
+	}
+	if len(app.Hunks) != 3 {
+		t.Fatalf("expected 3 hunks, got %d", len(app.Hunks))
+	}
+
+	want := []Hunk{
+		{OldStart: 3, OldCount: 1, NewStart: 3, NewCount: 1},
+		{OldStart: 10, OldCount: 0, NewStart: 11, NewCount: 2},
+		{OldStart: 20, OldCount: 2, NewStart: 22, NewCount: 0},
+	}
+	for i, h := range app.Hunks {
+		if h.OldStart != want[i].OldStart || h.OldCount != want[i].OldCount ||
+			h.NewStart != want[i].NewStart || h.NewCount != want[i].NewCount {
+			t.Errorf("hunk %d = %+v, want %+v", i, h, want[i])
+		}
+	}
+	if len(app.Hunks[1].Lines) != 2 || app.Hunks[1].Lines[0] != "+\tone()" {
+		t.Errorf("unexpected hunk lines: %q", app.Hunks[1].Lines)
+	}
+
+	if files[1].Path != "new.txt" || files[1].Modifiable {
+		t.Errorf("new.txt parsed as path=%q modifiable=%v", files[1].Path, files[1].Modifiable)
+	}
+}
+
+func TestParse_MalformedHeader(t *testing.T) {
+	_, err := Parse("diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ bogus @@\n")
+	if err == nil {
+		t.Error("expected error for malformed hunk header")
+	}
+}
+
+func TestRender_RenumbersAfterAppliedHunks(t *testing.T) {
+	files, err := Parse(sampleDiff)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	app := files[0]
+
+	// The addition at line 10 was applied first, so the deletion at 20 now
+	// starts two lines later; the typo fix above it is unaffected.
+	got := Render(app, []Hunk{app.Hunks[2], app.Hunks[0]}, []Hunk{app.Hunks[1]})
+	want := `diff --git a/app.go b/app.go
+index 1111111..2222222 100644
+--- a/app.go
++++ b/app.go
+@@ -3,1 +3,1 @@
+-	fmt.Println("helo")
++	fmt.Println("hello")
+@@ -22,2 +21,0 @@
+-	old()
+-	older()
+`
+	if got != want {
+		t.Errorf("Render mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRender_AdditionAfterAppliedChange(t *testing.T) {
+	files, err := Parse(sampleDiff)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	app := files[0]
+
+	got := Render(app, []Hunk{app.Hunks[1]}, []Hunk{app.Hunks[0], app.Hunks[2]})
+	want := `diff --git a/app.go b/app.go
+index 1111111..2222222 100644
+--- a/app.go
++++ b/app.go
+@@ -10,0 +11,2 @@
++	one()
++	two()
+`
+	if got != want {
+		t.Errorf("Render mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	if got := Render(File{Header: []string{"diff --git a/x b/x"}}, nil, nil); got != "" {
+		t.Errorf("expected empty patch, got %q", got)
+	}
+}